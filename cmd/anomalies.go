@@ -0,0 +1,417 @@
+/*****************************************************************************
+*
+*	File			: anomalies.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Fraud and anomalies, from the seed "anomalies", injected into the baskets and payments at their rate,
+*					: each a share of the baskets, without one the data is clean, as before:
+*
+*					: "anomalies": {"cardTesting": {"rate": 0.002, "burst": 10},
+*					:               "largeBasket": {"rate": 0.001, "factor": 10},
+*					:               "refundAbuse": {"rate": 0.002, "clerk": "10007"},
+*					:               "sweethearting": {"rate": 0.003, "items": 2},
+*					:               "duplicateFinTxn": {"rate": 0.001}}
+*
+*					:	card_testing		- the basket becomes a burst of burst baskets, one cheap item each, seconds
+*					:						  apart, anonymous, all paid by card with the same card number
+*					:	large_basket		- factor times the quantities of the basket
+*					:	refund_abuse		- the basket becomes a refund, negative quantities and amounts, by the one
+*					:						  clerk, at the clerk's store
+*					:	sweethearting		- items lines of the basket rung up at a zero price
+*					:	duplicate_fin_txn	- the payment reuses the finTransactionID of an earlier payment
+*
+*					: A basket gets at most one. Every anomalous invoice gets a ground-truth label, posted to
+*					: LabelTopicname / Labelcollection and the json_save labels file, the invoices without one are clean,
+*					: to score the detection rules' precision and recall against.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+
+	"cmd/types"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	anomalyCardTesting     = "card_testing"
+	anomalyLargeBasket     = "large_basket"
+	anomalyRefundAbuse     = "refund_abuse"
+	anomalySweethearting   = "sweethearting"
+	anomalyDuplicateFinTxn = "duplicate_fin_txn"
+
+	transactionRefund = "refund"
+
+	// How many of the latest finTransactionIDs a duplicate is picked from
+	recentFinTxns = 1000
+)
+
+// A basket to post, at its time
+type tpInjected struct {
+	basket *types.Pb_Basket
+	at     time.Time
+}
+
+type tpAnomalies struct {
+	anomalies types.TPAnomalies
+	clerk     int      // refundAbuse, the clerk's seed position
+	cheap     []int    // cardTesting, the seed positions of the cheapest products
+	finTxns   []string // the latest finTransactionIDs
+	kind      string   // the anomaly of the current basket, for its payments
+	card      string   // cardTesting, the current burst's card number
+}
+
+func newAnomalies(seed types.TPSeed) *tpAnomalies {
+
+	a := &tpAnomalies{anomalies: seed.Anomalies, clerk: -1}
+
+	if len(seed.Clerks) > 0 {
+		a.clerk = rand.Intn(len(seed.Clerks))
+	}
+	for i, clerk := range seed.Clerks {
+		if clerk.Id == seed.Anomalies.RefundAbuse.Clerk {
+			a.clerk = i
+		}
+	}
+
+	// The cheapest tenth of the products, card testers buy the least they can
+	for i := range seed.Products {
+		a.cheap = append(a.cheap, i)
+	}
+	sort.SliceStable(a.cheap, func(i, j int) bool { return seed.Products[a.cheap[i]].Price < seed.Products[a.cheap[j]].Price })
+	if len(a.cheap) > 0 {
+		a.cheap = a.cheap[:len(a.cheap)/10+1]
+	}
+
+	return a
+}
+
+// The anomalies for a refreshed seed, the latest finTransactionIDs and the refundAbuse clerk, if still a seed clerk,
+// carry over.
+func (a *tpAnomalies) reseed(seed types.TPSeed) *tpAnomalies {
+
+	fresh := newAnomalies(seed)
+	fresh.finTxns = a.finTxns
+	if seed.Anomalies.RefundAbuse.Clerk == "" && a.clerk >= 0 {
+		for i, clerk := range seed.Clerks {
+			if clerk.Id == varSeed.Clerks[a.clerk].Id {
+				fresh.clerk = i
+			}
+		}
+	}
+	return fresh
+}
+
+func (a *tpAnomalies) enabled() bool {
+	an := a.anomalies
+	return an.CardTesting.Rate > 0 || an.LargeBasket.Rate > 0 || an.RefundAbuse.Rate > 0 || an.Sweethearting.Rate > 0 || an.DuplicateFinTxn.Rate > 0
+}
+
+// The masked card number of a card payment
+func cardNumber() string {
+
+	n := fmt.Sprint(gofakeit.CreditCardNumber())
+	return n[:6] + strings.Repeat("*", len(n)-10) + n[len(n)-4:]
+}
+
+// The basket's discount, tax and totals, after its lines were changed.
+func retotal(pb_Basket *types.Pb_Basket) {
+
+	discount := 0.0
+	for _, item := range pb_Basket.BasketItems {
+		discount += item.Discount
+	}
+	pb_Basket.Discount = toFixed(discount, 2)
+	pb_Basket.Nett, pb_Basket.Vat = varTax.apply(pb_Basket.BasketItems)
+	pb_Basket.Total = toFixed(pb_Basket.Nett+pb_Basket.Vat+pb_Basket.ShippingFee, 2)
+}
+
+func label(pb_Basket *types.Pb_Basket, kind string, detail string) *types.PBAnomalyLabel {
+
+	return &types.PBAnomalyLabel{
+		LabelId:       uuid.New().String(),
+		InvoiceNumber: pb_Basket.InvoiceNumber,
+		AnomalyType:   kind,
+		Detail:        detail,
+		StoreId:       pb_Basket.Store.GetId(),
+		ClerkId:       pb_Basket.Clerk.GetId(),
+		TerminalPoint: pb_Basket.TerminalPoint,
+		SaleDateTime:  pb_Basket.SaleDateTime,
+		SaleTimestamp: pb_Basket.SaleTimestamp,
+	}
+}
+
+// The seed store of the basket
+func basketStore(pb_Basket *types.Pb_Basket) types.TPStoreStruct {
+
+	for _, store := range varSeed.Stores {
+		if store.Id == pb_Basket.Store.GetId() {
+			return store
+		}
+	}
+	return types.TPStoreStruct{Id: pb_Basket.Store.GetId(), Name: pb_Basket.Store.GetName()}
+}
+
+// Roll the basket's anomaly, if any, and apply it, at is the sale's time in the store's timezone. Returns the baskets
+// to post, the basket itself, changed or not, or a card testing burst, and their labels. The stock the basket took
+// follows the anomaly, see inventory.go.
+func (a *tpAnomalies) inject(pb_Basket *types.Pb_Basket, at time.Time) ([]tpInjected, []*types.PBAnomalyLabel) {
+
+	sales := []tpInjected{{basket: pb_Basket, at: at}}
+
+	a.kind = ""
+	roll := rand.Float64()
+	for _, anomaly := range []struct {
+		kind string
+		rate float64
+	}{
+		{anomalyCardTesting, a.anomalies.CardTesting.Rate},
+		{anomalyLargeBasket, a.anomalies.LargeBasket.Rate},
+		{anomalyRefundAbuse, a.anomalies.RefundAbuse.Rate},
+		{anomalySweethearting, a.anomalies.Sweethearting.Rate},
+		{anomalyDuplicateFinTxn, a.anomalies.DuplicateFinTxn.Rate},
+	} {
+		if roll < anomaly.rate {
+			a.kind = anomaly.kind
+			break
+		}
+		roll -= anomaly.rate
+	}
+
+	basket := pb_Basket
+	switch a.kind {
+	case anomalyCardTesting:
+		burst := a.anomalies.CardTesting.Burst
+		if burst < 1 {
+			burst = 10
+		}
+		a.card = cardNumber()
+
+		// The basket is not sold, the burst is, one item each
+		store := basketStore(pb_Basket)
+		varStock.undo(pb_Basket.InvoiceNumber)
+
+		var labels []*types.PBAnomalyLabel
+		sales = sales[:0]
+		for i := 0; i < burst; i++ {
+			// at is in the store's timezone already
+			at = at.Add(time.Duration(gofakeit.Number(5, 30)) * time.Second)
+
+			// The cheapest that is in stock
+			invoiceNumber := uuid.New().String()
+			cheapest := -1
+			for _, p := range rand.Perm(len(a.cheap)) {
+				if varStock.sell(varSeed, store, a.cheap[p], 1, invoiceNumber, at) > 0 {
+					cheapest = a.cheap[p]
+					break
+				}
+			}
+			if cheapest < 0 {
+				varStock.undo(invoiceNumber)
+				continue
+			}
+			product := varSeed.Products[cheapest]
+
+			b := proto.Clone(pb_Basket).(*types.Pb_Basket)
+			b.InvoiceNumber = invoiceNumber
+			b.Customer = nil
+			b.Delivery = nil
+			b.ShippingFee = 0
+			b.BasketItems = []*types.BasketItem{{
+				Id:          product.Id,
+				Name:        product.Name,
+				Brand:       product.Brand,
+				Category:    product.Category,
+				Price:       varCurr.price(varProf.price(store, product.Price), b.Currency),
+				Quantity:    1,
+				TaxCategory: varTax.category(product),
+			}}
+			retotal(b)
+
+			b.SaleDateTime = formatTimestamp(at)
+			b.SaleTimestamp = fmt.Sprint(at.UnixMilli())
+
+			sales = append(sales, tpInjected{basket: b, at: at})
+			labels = append(labels, label(b, a.kind, fmt.Sprintf("burst %d of %d on card %s", i+1, burst, a.card)))
+		}
+		return sales, labels
+
+	case anomalyLargeBasket:
+		factor := a.anomalies.LargeBasket.Factor
+		if factor <= 1 {
+			factor = 10
+		}
+		// The extra quantities come off the shelf too, as much as there is
+		store := basketStore(basket)
+		for _, item := range basket.BasketItems {
+			quantity := int32(float64(item.Quantity)*factor + 0.5)
+			if product, ok := varStock.product(item.Id); ok {
+				quantity = item.Quantity + int32(varStock.sell(varSeed, store, product, int(quantity-item.Quantity), basket.InvoiceNumber, at))
+			}
+			item.Discount = toFixed(item.Discount*float64(quantity)/float64(item.Quantity), 2)
+			item.Quantity = quantity
+		}
+		retotal(basket)
+		return sales, []*types.PBAnomalyLabel{label(basket, a.kind, fmt.Sprintf("quantities times %g", factor))}
+
+	case anomalyRefundAbuse:
+		// The basket is not sold, its items are refunded, back on the shelf of the clerk's store, on one of its
+		// terminals, at its prices and in its currency
+		varStock.undo(basket.InvoiceNumber)
+
+		clerk := varSeed.Clerks[a.clerk]
+		for i, store := range varSeed.Stores {
+			if store.Id == clerk.StoreId {
+				at = storeTime(store, at)
+				terminal, _ := varTerm.pick(i)
+				basket.Store = &types.Idstruct{Id: store.Id, Name: store.Name}
+				basket.TerminalPoint = terminal.Id
+				basket.TerminalType = terminal.Type
+			}
+		}
+		store := basketStore(basket)
+		basket.Currency = storeCurrency(store)
+		for _, item := range basket.BasketItems {
+			for _, product := range varSeed.Products {
+				if product.Id != item.Id {
+					continue
+				}
+				// The promotion discount, at the same share of the line
+				price := varCurr.price(varProf.price(store, product.Price), basket.Currency)
+				if item.Price > 0 {
+					item.Discount = toFixed(item.Discount*price/item.Price, 2)
+				}
+				item.Price = price
+				break
+			}
+		}
+		basket.Clerk = &types.Idstruct{Id: clerk.Id, Name: clerk.Name}
+		basket.Customer = nil
+		basket.Delivery = nil
+		basket.ShippingFee = 0
+		basket.TransactionType = transactionRefund
+		basket.SaleDateTime = formatTimestamp(at)
+		basket.SaleTimestamp = fmt.Sprint(at.UnixMilli())
+		for _, item := range basket.BasketItems {
+			varStock.restock(varSeed, store, item.Id, int(item.Quantity), basket.InvoiceNumber, at)
+			item.Quantity = -item.Quantity
+			item.Discount = -item.Discount
+		}
+		retotal(basket)
+		sales[0].at = at
+		return sales, []*types.PBAnomalyLabel{label(basket, a.kind, fmt.Sprintf("refund by clerk %s (%s)", clerk.Id, clerk.Name))}
+
+	case anomalySweethearting:
+		items := a.anomalies.Sweethearting.Items
+		if items < 1 {
+			items = 2
+		}
+		var free []string
+		for _, i := range rand.Perm(len(basket.BasketItems)) {
+			if len(free) == items {
+				break
+			}
+			item := basket.BasketItems[i]
+			item.Price = 0
+			item.Discount = 0
+			item.PromotionId = ""
+			free = append(free, item.Id)
+		}
+		retotal(basket)
+		return sales, []*types.PBAnomalyLabel{label(basket, a.kind, fmt.Sprintf("products %s at a zero price", strings.Join(free, ", ")))}
+
+	}
+	return sales, nil
+}
+
+// Apply the current basket's anomaly to its payment, for a duplicate finTransactionID it is only now it has a label.
+func (a *tpAnomalies) pay(pb_Basket *types.Pb_Basket, pb_Payment *types.Pb_Payment) *types.PBAnomalyLabel {
+
+	var l *types.PBAnomalyLabel
+
+	switch a.kind {
+	case anomalyCardTesting:
+		// Card testers pay by card, whatever the terminal
+		pb_Payment.Paid = toFixed(pb_Payment.Paid-pb_Payment.Rounding, 2)
+		pb_Payment.Rounding = 0
+		pb_Payment.PaymentMethod = "card"
+		pb_Payment.CardNumber = a.card
+
+	case anomalyDuplicateFinTxn:
+		if len(a.finTxns) > 0 {
+			duplicate := a.finTxns[rand.Intn(len(a.finTxns))]
+			l = label(pb_Basket, a.kind, fmt.Sprintf("finTransactionID %s of an earlier payment", duplicate))
+			pb_Payment.FinTransactionID = duplicate
+		}
+	}
+
+	a.finTxns = append(a.finTxns, pb_Payment.FinTransactionID)
+	if len(a.finTxns) > recentFinTxns {
+		a.finTxns = a.finTxns[1:]
+	}
+	return l
+}
+
+// The rates are shares that add up to no more than 1, and the refundAbuse clerk is a seed clerk.
+func validateAnomalies(seed types.TPSeed) []string {
+
+	var problems []string
+
+	anomalies := seed.Anomalies
+	total := 0.0
+	for _, anomaly := range []struct {
+		name    string
+		anomaly types.TPAnomaly
+	}{
+		{"cardTesting", anomalies.CardTesting},
+		{"largeBasket", anomalies.LargeBasket},
+		{"refundAbuse", anomalies.RefundAbuse},
+		{"sweethearting", anomalies.Sweethearting},
+		{"duplicateFinTxn", anomalies.DuplicateFinTxn},
+	} {
+		if anomaly.anomaly.Rate < 0 || anomaly.anomaly.Rate > 1 {
+			problems = append(problems, fmt.Sprintf("seed anomalies %s rate must be between 0 and 1, got %g", anomaly.name, anomaly.anomaly.Rate))
+		}
+		total += anomaly.anomaly.Rate
+	}
+	if total > 1 {
+		problems = append(problems, fmt.Sprintf("seed anomalies rates add up to %g, more than all the baskets", total))
+	}
+
+	if anomalies.CardTesting.Burst < 0 {
+		problems = append(problems, fmt.Sprintf("seed anomalies cardTesting burst must be >= 1 (0 => 10), got %d", anomalies.CardTesting.Burst))
+	}
+	if anomalies.LargeBasket.Factor != 0 && anomalies.LargeBasket.Factor <= 1 {
+		problems = append(problems, fmt.Sprintf("seed anomalies largeBasket factor must be > 1 (0 => 10), got %g", anomalies.LargeBasket.Factor))
+	}
+	if anomalies.Sweethearting.Items < 0 {
+		problems = append(problems, fmt.Sprintf("seed anomalies sweethearting items must be >= 1 (0 => 2), got %d", anomalies.Sweethearting.Items))
+	}
+
+	if clerk := anomalies.RefundAbuse.Clerk; clerk != "" {
+		found := false
+		for _, c := range seed.Clerks {
+			if c.Id == clerk {
+				found = true
+			}
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("seed anomalies refundAbuse clerk %s is not a seed clerk", clerk))
+		}
+	}
+
+	return problems
+}
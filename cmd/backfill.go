@@ -0,0 +1,157 @@
+/*****************************************************************************
+*
+*	File			: backfill.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Where the event time of the next sale comes from. produce runs on the wall clock, paced by
+*					: Rate/Sleep. backfill runs on a simulated clock over a past date range, as fast as the sinks take it:
+*
+*					: go run ./cmd backfill --env <env> --from 2026-07-01 --to 2026-09-30 [--rate 0.05] [--count N]
+*					: go run ./cmd backfill --env <env> --days 90
+*
+*					: The simulated time between sales follows Rate, sales per simulated second, scaled by the traffic
+*					: model, closed hours are skipped. The range runs from 00:00 on --from to the end of --to (or now), in
+*					: Timezone, the whole range unless --count stops it earlier, app.Testsize is ignored.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Backfill Rate when app.Rate is 0, one sale per simulated minute
+const backfillRate = 1.0 / 60
+
+// The event time, and traffic, of the next sale, !ok => we're done
+type tpClock interface {
+	next() (traffic tpTrafficAt, at time.Time, ok bool)
+	simulated() bool
+}
+
+// The wall clock, paced by Rate/Sleep
+type tpLiveClock struct {
+	pacer  *tpPacer
+	demand float64 // of the previous sale, 0 => first sale
+}
+
+func (c *tpLiveClock) next() (tpTrafficAt, time.Time, bool) {
+
+	if c.demand > 0 {
+		c.pacer.wait(c.demand)
+	}
+
+	traffic, now := varTraff.waitOpen()
+	c.demand = traffic.demand
+
+	return traffic, now, true
+}
+
+func (c *tpLiveClock) simulated() bool { return false }
+
+// Simulated time from..to
+type tpBackfillClock struct {
+	now  time.Time
+	to   time.Time
+	rate float64
+}
+
+func (c *tpBackfillClock) next() (tpTrafficAt, time.Time, bool) {
+
+	for {
+		traffic := varTraff.at(c.now)
+		if traffic.demand <= 0 {
+			// Everything is closed, try again the next minute
+			c.now = c.now.Truncate(time.Minute).Add(time.Minute)
+
+		} else {
+			// Poisson arrivals at rate * demand
+			gap := rand.ExpFloat64() / (c.rate * traffic.demand)
+			c.now = c.now.Add(time.Duration(gap * float64(time.Second)))
+
+		}
+
+		if !c.now.Before(c.to) {
+			return tpTrafficAt{}, c.now, false
+		}
+
+		if traffic = varTraff.at(c.now); traffic.demand > 0 {
+			return traffic, c.now, true
+		}
+	}
+}
+
+func (c *tpBackfillClock) simulated() bool { return true }
+
+func runBackfill(opts *tpOptions) {
+
+	opts.backfill = true
+	runLoader(opts)
+
+}
+
+// The simulated clock for backfill's --from/--to/--days, else the wall clock.
+func newClock(opts *tpOptions) tpClock {
+
+	if !opts.backfill {
+		return &tpLiveClock{pacer: newPacer(vGeneral.Rate)}
+	}
+
+	from, to, err := backfillRange(opts, time.Now())
+	if err != nil {
+		grpcLog.Fatalln("backfill:", err)
+
+	}
+
+	rate := vGeneral.Rate
+	if rate == 0 {
+		rate = backfillRate
+	}
+
+	grpcLog.Infoln("* Backfill From               :", from.Format(time.RFC3339))
+	grpcLog.Infoln("* Backfill To                 :", to.Format(time.RFC3339))
+	grpcLog.Infoln("* Backfill Rate (per sim sec) :", rate)
+
+	return &tpBackfillClock{now: from, to: to, rate: rate}
+}
+
+// The from..to of --from/--to or --days, in Timezone, to is exclusive.
+func backfillRange(opts *tpOptions, now time.Time) (from, to time.Time, err error) {
+
+	to = now
+	if opts.to != "" {
+		day, err := time.ParseInLocation(trafficDate, opts.to, zone(""))
+		if err != nil {
+			return from, to, err
+		}
+		to = day.AddDate(0, 0, 1)
+	}
+
+	switch {
+	case opts.from != "" && opts.days != 0:
+		return from, to, fmt.Errorf("use either --from or --days")
+
+	case opts.from != "":
+		if from, err = time.ParseInLocation(trafficDate, opts.from, zone("")); err != nil {
+			return from, to, err
+		}
+
+	case opts.days > 0:
+		y, m, d := to.In(zone("")).AddDate(0, 0, -opts.days).Date()
+		from = time.Date(y, m, d, 0, 0, 0, 0, zone(""))
+
+	default:
+		return from, to, fmt.Errorf("--from or --days > 0 is required")
+	}
+
+	if !from.Before(to) {
+		return from, to, fmt.Errorf("the range %s up to %s is empty", from.Format(trafficDate), to.Format(time.RFC3339))
+	}
+	return from, to, nil
+}
@@ -0,0 +1,328 @@
+/*****************************************************************************
+*
+*	File			: cli.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Command line, subcommands and their flags.
+*
+*					: go run ./cmd <command> [flags] [<section>.<key>=<value> ...]
+*
+*					: The original "go run ./cmd <env>" is still accepted, as "produce --env <env>".
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	glog "google.golang.org/grpc/grpclog"
+)
+
+const version = "2.0.0"
+
+// What the command line asked for, shared by all the commands.
+type tpOptions struct {
+	env        string
+	configDir  string
+	count      int     // overrides app.Testsize, -1 => as configured
+	rate       float64 // overrides app.Rate, -1 => as configured
+	dryRun     bool
+	runId      string
+	scenario   string // scenario file
+	backfill   bool   // produce on a simulated clock over from..to
+	from       string // backfill range, YYYY-MM-DD
+	to         string
+	days       int
+	stores     int // seed generate sizes
+	clerks     int
+	products   int
+	out        string   // seed generate output file
+	randomSeed int64    // seed generate gofakeit seed, 0 => random
+	settings   []string // <section>.<key>=<value> overrides, --set and trailing arguments
+	args       []string // whatever positional arguments remain
+}
+
+// --set can be repeated
+type tpSettings []string
+
+func (s *tpSettings) String() string { return strings.Join(*s, ",") }
+
+func (s *tpSettings) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+type tpCommand struct {
+	name    string
+	args    string
+	help    string
+	flags   []string // which of the optional flags apply, --env, --config-dir and --set always do
+	run     func(opts *tpOptions)
+	needEnv bool
+}
+
+var commands []tpCommand
+
+func init() {
+
+	commands = []tpCommand{
+		{"produce", "", "Generate baskets and payments into the enabled Kafka/Mongo/file sinks", []string{"count", "rate", "dry-run"}, runLoader, true},
+		{"backfill", "--from <YYYY-MM-DD> [--to <YYYY-MM-DD>] | --days <n>", "Generate a past date range on a simulated clock, as fast as the sinks take it", []string{"from", "to", "days", "count", "rate", "dry-run"}, runBackfill, true},
+		{"replay", "--run-id <runId>", "Re-post a run saved with Json_to_file = 1, its sales, stock, price, order and label documents, into the enabled sinks", []string{"run-id", "rate", "dry-run"}, runReplay, true},
+		{"scenario", "--file <scenario file>", "Run the phases of a scenario file, one after the other or overlapping, each with its own settings and sinks", []string{"file", "dry-run"}, runScenario, true},
+		{"validate-config", "", "Load and validate the configuration and seed, then exit", nil, runValidateConfig, true},
+		{"seed", "show | validate | generate", "Seed data commands, show prints a summary of the seed file, validate checks it, generate writes a generated seed file", []string{"stores", "clerks", "products", "out", "random-seed"}, runSeed, true},
+		{"topics", "", "Create the basket, payment, inventory, price, order and label Kafka topics if they do not exist", nil, runTopics, true},
+		{"verify", "--run-id <runId>", "Reconcile a run's manifest against the Mongo collections", []string{"run-id"}, runVerify, true},
+		{"latency", "", "Watch Mongo and report the end-to-end latency of produced documents", nil, runLatency, true},
+		{"version", "", "Print the version", nil, runVersion, false},
+	}
+}
+
+func usage() {
+
+	w := flag.CommandLine.Output()
+	fmt.Fprintf(w, "Usage: go run ./cmd <command> [flags] [<section>.<key>=<value> ...]\n\n")
+	fmt.Fprintf(w, "Commands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-16s %s\n", c.name, c.help)
+	}
+	fmt.Fprintf(w, "\nRun go run ./cmd <command> --help for the command's flags.\n")
+	fmt.Fprintf(w, "Values can be overridden with --set app.testsize=100, trailing app.testsize=100 arguments or\n")
+	fmt.Fprintf(w, "MONGOCREATOR_APP_TESTSIZE=100 environment variables, see README.md.\n")
+}
+
+func findCommand(name string) (tpCommand, bool) {
+
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return tpCommand{}, false
+}
+
+// Parse the command's flags and positional arguments into opts.
+func parseCommand(c tpCommand, arguments []string) *tpOptions {
+
+	opts := &tpOptions{count: -1, rate: -1}
+	var settings tpSettings
+
+	fs := flag.NewFlagSet(c.name, flag.ExitOnError)
+	fs.Usage = func() {
+		w := fs.Output()
+		fmt.Fprintf(w, "Usage: go run ./cmd %s %s [flags] [<section>.<key>=<value> ...]\n\n", c.name, c.args)
+		fmt.Fprintf(w, "%s\n\nFlags:\n", c.help)
+		fs.PrintDefaults()
+	}
+
+	if c.needEnv {
+		fs.StringVar(&opts.env, "env", os.Getenv(envPrefix+"_ENV"), "environment, reads <config-dir>/<env>.yaml|.toml|.json or <env>_app.json etc. (or "+envPrefix+"_ENV)")
+		fs.StringVar(&opts.configDir, "config-dir", ".", "directory holding the configuration files, the SeedFile is relative to it")
+		fs.Var(&settings, "set", "override a configuration value, <section>.<key>=<value>, can be repeated")
+	}
+
+	for _, name := range c.flags {
+		switch name {
+		case "count":
+			fs.IntVar(&opts.count, "count", -1, "number of baskets to produce, overrides app.Testsize, 0 => run continuously (backfill: the whole range)")
+		case "rate":
+			fs.Float64Var(&opts.rate, "rate", -1, "records per second, overrides app.Rate, 0 => paced by app.Sleep (backfill: per simulated second)")
+		case "dry-run":
+			fs.BoolVar(&opts.dryRun, "dry-run", false, "do not post to Kafka/Mongo/files, print the documents to stdout instead")
+		case "from":
+			fs.StringVar(&opts.from, "from", "", "first day of the backfill, YYYY-MM-DD")
+		case "to":
+			fs.StringVar(&opts.to, "to", "", "last day of the backfill, YYYY-MM-DD, default up to now")
+		case "days":
+			fs.IntVar(&opts.days, "days", 0, "backfill the last n days, instead of --from")
+		case "stores":
+			fs.IntVar(&opts.stores, "stores", 15, "seed generate, number of stores")
+		case "clerks":
+			fs.IntVar(&opts.clerks, "clerks", 30, "seed generate, number of clerks, at least one per store")
+		case "products":
+			fs.IntVar(&opts.products, "products", 100, "seed generate, number of products")
+		case "out":
+			fs.StringVar(&opts.out, "out", "generated_seed.json", "seed generate, the seed file to write")
+		case "random-seed":
+			fs.Int64Var(&opts.randomSeed, "random-seed", 0, "seed generate, the same value generates the same seed, 0 => random")
+		case "file":
+			fs.StringVar(&opts.scenario, "file", "", "the scenario file, YAML, TOML or JSON, see example/scenario.json")
+		case "run-id":
+			fs.StringVar(&opts.runId, "run-id", "", "the runId printed by produce")
+		}
+	}
+
+	// flag stops at the first positional argument, we allow flags and positionals to be mixed, ie seed show --env loc
+	// Positional <section>.<key>=<value> are overrides, anything else an argument to the command.
+	for fs.Parse(arguments); fs.NArg() > 0; fs.Parse(arguments) {
+		arg := fs.Arg(0)
+		arguments = fs.Args()[1:]
+
+		if strings.Contains(arg, "=") {
+			settings = append(settings, arg)
+		} else {
+			opts.args = append(opts.args, arg)
+		}
+	}
+	opts.settings = settings
+
+	// verify <env> <runId> and latency <env>, as before the flags
+	if (c.name == "verify" || c.name == "latency") && opts.env == "" && len(opts.args) > 0 {
+		opts.env, opts.args = opts.args[0], opts.args[1:]
+	}
+	if c.name == "verify" && opts.runId == "" && len(opts.args) > 0 {
+		opts.runId, opts.args = opts.args[0], opts.args[1:]
+	}
+
+	// seed generate needs no configuration
+	generate := c.name == "seed" && len(opts.args) > 0 && opts.args[0] == "generate"
+
+	if c.needEnv && opts.env == "" && !generate {
+		fmt.Fprintf(fs.Output(), "%s: --env is required\n\n", c.name)
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	// The flags are simply overrides, applied after --set and the trailing arguments.
+	if opts.count >= 0 {
+		opts.settings = append(opts.settings, fmt.Sprintf("app.testsize=%d", opts.count))
+	}
+	if opts.rate >= 0 {
+		opts.settings = append(opts.settings, fmt.Sprintf("app.rate=%g", opts.rate))
+	}
+
+	return opts
+}
+
+func runVersion(opts *tpOptions) {
+	fmt.Printf("MongoCreator GoProducer %s (%s %s/%s)\n", version, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}
+
+func runValidateConfig(opts *tpOptions) {
+
+	loadSettings(opts, false, false)
+
+	seed := loadSeed(vGeneral.SeedFile)
+	if problems := validateSeedConfig(vGeneral, seed); len(problems) > 0 {
+		fatalProblems("Configuration and Seed do not match:", problems)
+
+	}
+
+	grpcLog.Infoln("* Configuration OK            :", opts.env)
+
+}
+
+func runSeed(opts *tpOptions) {
+
+	action := "show"
+	if len(opts.args) > 0 {
+		action = opts.args[0]
+	}
+
+	switch action {
+	case "show":
+		loadSettings(opts, false, false)
+		seed := loadSeed(vGeneral.SeedFile)
+
+		grpcLog.Infoln("* Seed File                   :", vGeneral.SeedFile)
+		grpcLog.Infoln("* Stores                      :", len(seed.Stores))
+		grpcLog.Infoln("* Clerks                      :", len(seed.Clerks))
+		grpcLog.Infoln("* Products                    :", len(seed.Products))
+
+		// What the Distribution makes of it
+		pickers := newPickers(seed)
+		order := make([]int, len(seed.Products))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool { return pickers.products.share(order[a]) > pickers.products.share(order[b]) })
+
+		grpcLog.Infoln("* Distribution                :", vGeneral.Distribution)
+		top := 0.0
+		for n, i := range order {
+			if n == 10 {
+				break
+			}
+			top += pickers.products.share(i)
+			grpcLog.Infoln(fmt.Sprintf("*   %5.2f%%  %s", pickers.products.share(i)*100, seed.Products[i].Name))
+		}
+		grpcLog.Infoln(fmt.Sprintf("* Top 10 products share       : %.2f%%", top*100))
+
+	case "validate":
+		runSeedValidate(opts)
+
+	case "generate":
+		runSeedGenerate(opts)
+
+	default:
+		grpcLog.Fatalln("Unknown seed action", action, ", expected show, validate or generate")
+
+	}
+}
+
+func runTopics(opts *tpOptions) {
+
+	loadSettings(opts, true, false)
+	CreateTopic(vKafka)
+
+}
+
+func main() {
+
+	flag.Usage = usage
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+
+	}
+
+	name := os.Args[1]
+	arguments := os.Args[2:]
+
+	switch name {
+	case "help", "-h", "-help", "--help":
+		usage()
+		return
+
+	}
+
+	c, ok := findCommand(name)
+	if !ok {
+		if strings.HasPrefix(name, "-") {
+			usage()
+			os.Exit(2)
+		}
+		// go run ./cmd <env> [...], the original way of running the producer
+		c, _ = findCommand("produce")
+		arguments = append([]string{"--env", name}, arguments...)
+	}
+
+	opts := parseCommand(c, arguments)
+
+	// Keep stdout for the documents on a dry run
+	if opts.dryRun {
+		grpcLog = glog.NewLoggerV2(os.Stderr, os.Stderr, os.Stderr)
+	}
+
+	if c.needEnv {
+		banner()
+		grpcLog.Info("****** Starting           *****")
+	}
+
+	c.run(opts)
+
+	if c.needEnv {
+		grpcLog.Info("****** Completed          *****")
+	}
+}
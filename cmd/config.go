@@ -0,0 +1,516 @@
+/*****************************************************************************
+*
+*	File			: config.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Configuration loading, one place for all of it.
+*
+*					: The configuration is read from a single <env>.yaml, <env>.yml, <env>.toml or <env>.json file with
+*					: an app, kafka and mongo section, the same values as the legacy files. If none of those exist we
+*					: fall back to the legacy <env>_app.json, <env>_kafka.json and <env>_mongo.json files.
+*
+*					: Values are then overridden, in order:
+*					:	1. MONGOCREATOR_<SECTION>_<KEY> environment variables, ie MONGOCREATOR_APP_TESTSIZE=100,
+*					:	   MONGOCREATOR_KAFKA_SASL_PASSWORD=..., MONGOCREATOR_MONGO_USERNAME=...
+*					:	   the legacy Sasl_username/Sasl_password/mongo_username/mongo_password are still honoured.
+*					:	2. command line <section>.<key>=<value> arguments or --set, ie app.testsize=100, then --count/--rate
+*
+*					: after which the whole lot is validated, all problems reported at once.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ghodss/yaml"
+
+	"cmd/types"
+)
+
+const envPrefix = "MONGOCREATOR"
+
+// Unified configuration file extensions, in the order we look for them.
+var configExtensions = []string{".yaml", ".yml", ".toml", ".json"}
+
+// Read a YAML, TOML or JSON file into target. JSON is read as YAML, so our # comments keep on working,
+// TOML is converted to JSON first so all three decode the same (case insensitive) way.
+func readConfigFile(fileName string, target interface{}) error {
+
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+
+	if strings.EqualFold(filepath.Ext(fileName), ".toml") {
+		var values map[string]interface{}
+		if _, err = toml.Decode(string(data), &values); err != nil {
+			return err
+		}
+		if data, err = json.Marshal(values); err != nil {
+			return err
+		}
+	}
+
+	return yaml.Unmarshal(data, target)
+}
+
+// Find and read the configuration for env from configDir, unified file first, then the legacy file set.
+func readConfig(configDir string, env string) (cfg types.TPConfig, files []string, err error) {
+
+	for _, ext := range configExtensions {
+		fileName := filepath.Join(configDir, env+ext)
+		if _, err = os.Stat(fileName); err == nil {
+			if err = readConfigFile(fileName, &cfg); err != nil {
+				return cfg, nil, fmt.Errorf("error reading config file %s: %w", fileName, err)
+			}
+			return cfg, []string{fileName}, nil
+		}
+	}
+
+	// Legacy layout, the kafka and mongo files are only needed when enabled, so they may be missing.
+	legacy := []struct {
+		suffix   string
+		target   interface{}
+		required bool
+	}{
+		{"_app.json", &cfg.App, true},
+		{"_kafka.json", &cfg.Kafka, false},
+		{"_mongo.json", &cfg.Mongo, false},
+	}
+
+	for _, l := range legacy {
+		fileName := filepath.Join(configDir, env+l.suffix)
+		if _, err = os.Stat(fileName); err != nil {
+			if l.required {
+				return cfg, nil, fmt.Errorf("no configuration found for %s, expected %s.yaml|.toml|.json or %s", env, env, fileName)
+			}
+			continue
+		}
+
+		if err = readConfigFile(fileName, l.target); err != nil {
+			return cfg, nil, fmt.Errorf("error reading config file %s: %w", fileName, err)
+		}
+		files = append(files, fileName)
+	}
+
+	return cfg, files, nil
+}
+
+// The struct behind a config section name.
+func configSection(cfg *types.TPConfig, section string) (reflect.Value, bool) {
+
+	switch strings.ToLower(section) {
+	case "app":
+		return reflect.ValueOf(&cfg.App).Elem(), true
+	case "kafka":
+		return reflect.ValueOf(&cfg.Kafka).Elem(), true
+	case "mongo":
+		return reflect.ValueOf(&cfg.Mongo).Elem(), true
+	}
+	return reflect.Value{}, false
+}
+
+// Parse value into the (int, float, string) field
+func setField(f reflect.Value, value string) error {
+
+	switch f.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", value)
+		}
+		f.SetInt(n)
+
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		f.SetFloat(n)
+
+	case reflect.String:
+		f.SetString(value)
+
+	default:
+		return fmt.Errorf("can't be set from the environment or command line")
+	}
+
+	return nil
+}
+
+// Set <section>.<key> to value, key is matched case insensitive against the field names.
+func applySetting(cfg *types.TPConfig, path string, value string) error {
+
+	parts := strings.SplitN(path, ".", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("%s: expected <section>.<key>, ie app.testsize", path)
+	}
+
+	s, ok := configSection(cfg, parts[0])
+	if !ok {
+		return fmt.Errorf("%s: unknown section %q, expected app, kafka or mongo", path, parts[0])
+	}
+
+	for i := 0; i < s.NumField(); i++ {
+		if strings.EqualFold(s.Type().Field(i).Name, parts[1]) {
+			if err := setField(s.Field(i), value); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s: unknown key %q in section %s", path, parts[1], parts[0])
+}
+
+// MONGOCREATOR_<SECTION>_<KEY> environment variables, for every field of every section.
+func applyEnvOverrides(cfg *types.TPConfig) []string {
+
+	var problems []string
+
+	// The original secret variables, before we had the generic ones.
+	legacy := map[string]string{
+		"Sasl_username":  "kafka.Sasl_username",
+		"Sasl_password":  "kafka.Sasl_password",
+		"mongo_username": "mongo.Username",
+		"mongo_password": "mongo.Password",
+	}
+	for env, path := range legacy {
+		if value, ok := os.LookupEnv(env); ok && value != "" {
+			applySetting(cfg, path, value)
+		}
+	}
+
+	for _, section := range []string{"app", "kafka", "mongo"} {
+		s, _ := configSection(cfg, section)
+
+		for i := 0; i < s.NumField(); i++ {
+			name := s.Type().Field(i).Name
+			env := fmt.Sprintf("%s_%s_%s", envPrefix, strings.ToUpper(section), strings.ToUpper(name))
+
+			if value, ok := os.LookupEnv(env); ok {
+				if err := setField(s.Field(i), value); err != nil {
+					problems = append(problems, fmt.Sprintf("%s: %s", env, err))
+				}
+			}
+		}
+	}
+
+	return problems
+}
+
+// Everything we derive from the configured values, rather than read. The SeedFile and a csv Seed_location are
+// relative to configDir.
+func deriveConfig(cfg *types.TPConfig, files []string, configDir string) error {
+
+	var err error
+
+	cfg.App.CurrentPath, err = os.Getwd()
+	if err != nil {
+		return fmt.Errorf("problem retrieving current path: %w", err)
+	}
+
+	cfg.App.OSName = runtime.GOOS
+
+	cfg.App.Hostname, err = os.Hostname()
+	if err != nil {
+		return fmt.Errorf("can't retrieve hostname: %w", err)
+	}
+
+	if cfg.App.SeedFile != "" && !filepath.IsAbs(cfg.App.SeedFile) {
+		seedFile, err := filepath.Abs(filepath.Join(configDir, cfg.App.SeedFile))
+		if err != nil {
+			return fmt.Errorf("problem resolving SeedFile %s: %w", cfg.App.SeedFile, err)
+		}
+		cfg.App.SeedFile = seedFile
+	}
+
+	if cfg.App.Seed_source == seedSourceCsv && cfg.App.Seed_location != "" && !filepath.IsAbs(cfg.App.Seed_location) {
+		seedLocation, err := filepath.Abs(filepath.Join(configDir, cfg.App.Seed_location))
+		if err != nil {
+			return fmt.Errorf("problem resolving Seed_location %s: %w", cfg.App.Seed_location, err)
+		}
+		cfg.App.Seed_location = seedLocation
+	}
+
+	if (cfg.App.Json_to_file == 1 || cfg.App.Manifest == 1) && !filepath.IsAbs(cfg.App.Output_path) {
+		cfg.App.Output_path = fmt.Sprintf("%s%s%s", cfg.App.CurrentPath, pathSep, cfg.App.Output_path)
+	}
+
+	cfg.App.KafkaConfigFile = strings.Join(files, ", ")
+	cfg.App.MongoConfigFile = cfg.App.KafkaConfigFile
+	for _, fileName := range files {
+		if strings.HasSuffix(fileName, "_kafka.json") {
+			cfg.App.KafkaConfigFile = fileName
+		}
+		if strings.HasSuffix(fileName, "_mongo.json") {
+			cfg.App.MongoConfigFile = fileName
+		}
+	}
+
+	// An explicitly configured Uri wins
+	if cfg.Mongo.Uri == "" {
+		if cfg.Mongo.Username != "" {
+			cfg.Mongo.Uri = fmt.Sprintf("%s://%s:%s@%s&w=majority", cfg.Mongo.Root, cfg.Mongo.Username, cfg.Mongo.Password, cfg.Mongo.Url)
+
+		} else {
+			cfg.Mongo.Uri = fmt.Sprintf("%s://%s&w=majority", cfg.Mongo.Root, cfg.Mongo.Url)
+		}
+	}
+
+	return nil
+}
+
+// Check the configuration hangs together, kafkaNeeded/mongoNeeded for the commands that use Kafka/Mongo regardless
+// of KafkaEnabled/MongoAtlasEnabled.
+func validateConfig(cfg *types.TPConfig, kafkaNeeded bool, mongoNeeded bool) []string {
+
+	var problems []string
+	add := func(format string, a ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, a...))
+	}
+
+	flag := func(name string, v int) {
+		if v != 0 && v != 1 {
+			add("app.%s must be 0 or 1, got %d", name, v)
+		}
+	}
+
+	app := cfg.App
+	if app.Debuglevel < 0 || app.Debuglevel > 4 {
+		add("app.Debuglevel must be between 0 and 4, got %d", app.Debuglevel)
+	}
+	if app.Testsize < 0 {
+		add("app.Testsize must be >= 0 (0 => run continuously), got %d", app.Testsize)
+	}
+	if app.Sleep < 0 {
+		add("app.Sleep must be >= 0 milliseconds, got %d", app.Sleep)
+	}
+	if app.Vatrate < 0 || app.Vatrate >= 1 {
+		add("app.Vatrate must be a fraction between 0 and 1, ie 0.15, got %g", app.Vatrate)
+	}
+	if app.Store < 0 {
+		add("app.Store must be >= 0, got %d", app.Store)
+	}
+	if app.Max_items_basket < 1 {
+		add("app.Max_items_basket must be >= 1, got %d", app.Max_items_basket)
+	}
+	if app.Max_quantity < 1 {
+		add("app.Max_quantity must be >= 1, got %d", app.Max_quantity)
+	}
+	if app.SeedFile != "" {
+		if _, err := os.Stat(app.SeedFile); err != nil {
+			add("app.SeedFile %s: %s", app.SeedFile, err)
+		}
+	}
+
+	// The SeedFile is only required when it is the seed's only source, see seedsource.go
+	problems = append(problems, validateSeedSource(app)...)
+	problems = append(problems, validateFaults(app)...)
+
+	flag("EchoConfig", app.EchoConfig)
+	flag("EchoSeed", app.EchoSeed)
+	flag("KafkaEnabled", app.KafkaEnabled)
+	flag("MongoAtlasEnabled", app.MongoAtlasEnabled)
+	flag("Json_to_file", app.Json_to_file)
+	flag("Manifest", app.Manifest)
+	flag("Latency", app.Latency)
+	flag("Tax_inclusive", app.Tax_inclusive)
+
+	if app.Json_to_file == 1 || app.Manifest == 1 {
+		if info, err := os.Stat(app.Output_path); err != nil || !info.IsDir() {
+			add("app.Output_path %s must be an existing directory when Json_to_file or Manifest = 1", app.Output_path)
+		}
+	}
+
+	if app.Late_payments < 0 || app.Late_payments > 1 {
+		add("app.Late_payments must be a fraction between 0 and 1, got %g", app.Late_payments)
+	}
+	if app.Late_delay < 0 {
+		add("app.Late_delay must be >= 0 minutes (0 => 60), got %d", app.Late_delay)
+	}
+
+	if app.Rate < 0 {
+		add("app.Rate must be >= 0 records/second (0 => paced by Sleep), got %g", app.Rate)
+	}
+
+	if app.KafkaEnabled == 1 || kafkaNeeded {
+		kafka := cfg.Kafka
+		if kafka.Bootstrapservers == "" {
+			add("kafka.Bootstrapservers is required when KafkaEnabled = 1")
+		}
+		if kafka.SchemaRegistryURL == "" {
+			add("kafka.SchemaRegistryURL is required when KafkaEnabled = 1")
+		}
+		if kafka.BasketTopicname == "" || kafka.PaymentTopicname == "" {
+			add("kafka.BasketTopicname and kafka.PaymentTopicname are required when KafkaEnabled = 1")
+		}
+		if kafka.Numpartitions < 1 {
+			add("kafka.Numpartitions must be >= 1, got %d", kafka.Numpartitions)
+		}
+		if kafka.Replicationfactor < 1 {
+			add("kafka.Replicationfactor must be >= 1, got %d", kafka.Replicationfactor)
+		}
+		if kafka.Flush_interval < 1 {
+			add("kafka.Flush_interval must be >= 1, got %d", kafka.Flush_interval)
+		}
+		if _, err := time.ParseDuration(kafka.Parseduration); err != nil {
+			add("kafka.Parseduration must be a duration, ie 60s, got %q", kafka.Parseduration)
+		}
+		if kafka.Sasl_mechanisms != "" && (kafka.Sasl_username == "" || kafka.Sasl_password == "") {
+			add("kafka.Sasl_username and kafka.Sasl_password are required with Sasl_mechanisms %s, see MONGOCREATOR_KAFKA_SASL_PASSWORD", kafka.Sasl_mechanisms)
+		}
+	}
+
+	if app.MongoAtlasEnabled == 1 || mongoNeeded || app.Seed_source == seedSourceMongo || app.Latency == 1 {
+		mongo := cfg.Mongo
+		if mongo.Url == "" && mongo.Uri == "" {
+			add("mongo.Url is required")
+		}
+		if mongo.Datastore == "" {
+			add("mongo.Datastore is required")
+		}
+		if mongo.Batch_size < 1 {
+			add("mongo.Batch_size must be >= 1, got %d", mongo.Batch_size)
+		}
+
+		switch mongo.Modelling {
+		case "", modelSeparate:
+			if mongo.Basketcollection == "" || mongo.Paymentcollection == "" {
+				add("mongo.Basketcollection and mongo.Paymentcollection are required with Modelling %s", modelSeparate)
+			}
+		case modelUpsert:
+			if mongo.Basketcollection == "" {
+				add("mongo.Basketcollection is required with Modelling %s", modelUpsert)
+			}
+		case modelEmbedded:
+			if mongo.Salescollection == "" {
+				add("mongo.Salescollection is required with Modelling %s", modelEmbedded)
+			}
+		default:
+			add("mongo.Modelling must be %s, %s or %s, got %q", modelSeparate, modelEmbedded, modelUpsert, mongo.Modelling)
+		}
+	}
+
+	return problems
+}
+
+// Checks that need both the configuration and the seed data.
+func validateSeedConfig(app types.Tp_general, seed types.TPSeed) []string {
+
+	var problems []string
+
+	// The seed's own problems have stopped us in loadSeed already, see seed.go
+	if app.Store >= len(seed.Stores) {
+		problems = append(problems, fmt.Sprintf("app.Store %d is out of range, the seed has %d stores (positions 0..%d)", app.Store, len(seed.Stores), len(seed.Stores)-1))
+	}
+
+	problems = append(problems, validateDistribution(app, seed)...)
+	problems = append(problems, validateRoster(seed)...)
+	problems = append(problems, validateTerminals(app, seed)...)
+	problems = append(problems, validateTraffic(seed)...)
+	problems = append(problems, validateZones(app, seed)...)
+	problems = append(problems, validateCustomers(app)...)
+	problems = append(problems, validatePromotions(seed)...)
+	problems = append(problems, validateTax(app, seed)...)
+	problems = append(problems, validateCurrencies(app, seed)...)
+	problems = append(problems, validateInventory(seed)...)
+	problems = append(problems, validatePricing(seed)...)
+	problems = append(problems, validateOnline(seed)...)
+	problems = append(problems, validateAnomalies(seed)...)
+	problems = append(problems, validateProfiles(seed)...)
+
+	return problems
+}
+
+func fatalProblems(heading string, problems []string) {
+
+	grpcLog.Errorln(heading)
+	for _, problem := range problems {
+		grpcLog.Errorln("*   ", problem)
+	}
+	os.Exit(1)
+
+}
+
+// Load, override, derive and validate the configuration for opts.env, into vGeneral, vKafka and vMongodb.
+// opts.settings are the command line <section>.<key>=<value> overrides, kafkaNeeded and mongoNeeded for the
+// commands that talk to Kafka or Mongo regardless of KafkaEnabled/MongoAtlasEnabled.
+func loadSettings(opts *tpOptions, kafkaNeeded bool, mongoNeeded bool) {
+
+	grpcLog.Info("*")
+	grpcLog.Info("* Called with Argument => ", opts.env)
+	grpcLog.Info("*")
+
+	cfg, files, err := readConfig(opts.configDir, opts.env)
+	if err != nil {
+		grpcLog.Fatalln(err)
+
+	}
+
+	problems := applyEnvOverrides(&cfg)
+	for _, setting := range opts.settings {
+		parts := strings.SplitN(setting, "=", 2)
+		if len(parts) != 2 {
+			problems = append(problems, fmt.Sprintf("%s: expected <section>.<key>=<value>", setting))
+			continue
+		}
+		if err := applySetting(&cfg, parts[0], parts[1]); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	// A dry run prints the documents, nothing is posted or written.
+	if opts.dryRun {
+		cfg.App.KafkaEnabled = 0
+		cfg.App.MongoAtlasEnabled = 0
+		cfg.App.Json_to_file = 0
+		cfg.App.Manifest = 0
+		cfg.App.Latency = 0
+	}
+
+	if err = deriveConfig(&cfg, files, opts.configDir); err != nil {
+		grpcLog.Fatalln(err)
+
+	}
+
+	problems = append(problems, validateConfig(&cfg, kafkaNeeded, mongoNeeded)...)
+	if len(problems) > 0 {
+		fatalProblems(fmt.Sprintf("Configuration %s is invalid:", strings.Join(files, ", ")), problems)
+
+	}
+
+	vGeneral = cfg.App
+	vKafka = cfg.Kafka
+	vMongodb = cfg.Mongo
+
+	if vGeneral.EchoConfig == 1 {
+		grpcLog.Infoln("*")
+		grpcLog.Infoln("* Config:")
+		grpcLog.Infoln("* Current path:", vGeneral.CurrentPath)
+		grpcLog.Infoln("* Config File :", strings.Join(files, ", "))
+		grpcLog.Infoln("*")
+
+		printConfig(vGeneral)
+		if vGeneral.KafkaEnabled == 1 || kafkaNeeded {
+			printKafkaConfig(vKafka)
+		}
+		if vGeneral.MongoAtlasEnabled == 1 || mongoNeeded || vGeneral.Latency == 1 {
+			printMongoConfig(vMongodb)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+/*****************************************************************************
+*
+*	File			: currency.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Currencies. The seed prices are in *_app.json Currency, a seed store can carry a currency of its
+*					: own, its prices are converted at the seed "currencies" rates, all of a basket's amounts are in
+*					: its store's currency:
+*
+*					: "currencies": [{"code": "ZAR", "rate": 1, "cashRounding": 0.10},
+*					:                {"code": "USD", "rate": 18.25, "cashRounding": 0.01}]
+*
+*					: rate is the value of 1 unit in Currency. Cash payments are rounded to the currency's cashRounding,
+*					: the payment carries the rounding. Foreign_cards of the card payments are made with a card in
+*					: another of the currencies, the payment then carries the card currency, the FX rate and the amount
+*					: in the card currency. Blank Currency => no currencies, as before.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"cmd/types"
+)
+
+type tpCurrencies struct {
+	byCode map[string]types.TPCurrency
+	codes  []string // in seed order
+}
+
+func newCurrencies(seed types.TPSeed) *tpCurrencies {
+
+	c := &tpCurrencies{byCode: make(map[string]types.TPCurrency)}
+	for _, currency := range seed.Currencies {
+		c.byCode[currency.Code] = currency
+		c.codes = append(c.codes, currency.Code)
+	}
+	return c
+}
+
+// The store's currency, its own else Currency
+func storeCurrency(store types.TPStoreStruct) string {
+
+	if store.Currency != "" {
+		return store.Currency
+	}
+	return vGeneral.Currency
+}
+
+// Value of 1 unit of the currency in Currency, 1 for Currency itself or if we don't know it
+func (c *tpCurrencies) rate(code string) float64 {
+
+	if currency, ok := c.byCode[code]; ok && currency.Rate > 0 && code != vGeneral.Currency {
+		return currency.Rate
+	}
+	return 1
+}
+
+// amount in from, in to
+func (c *tpCurrencies) convert(amount float64, from string, to string) float64 {
+
+	if from == to {
+		return amount
+	}
+	return toFixed(amount*c.rate(from)/c.rate(to), 2)
+}
+
+// A seed price, in Currency, in the currency
+func (c *tpCurrencies) price(price float64, code string) float64 {
+	return c.convert(price, vGeneral.Currency, code)
+}
+
+// Round a cash amount to the currency's cashRounding
+func (c *tpCurrencies) cashRound(amount float64, code string) float64 {
+
+	step := c.byCode[code].CashRounding
+	if step <= 0 {
+		return amount
+	}
+	return toFixed(math.Round(amount/step)*step, 2)
+}
+
+// A card currency for a card payment in the currency, blank => a local card
+func (c *tpCurrencies) cardCurrency(code string) string {
+
+	if len(c.codes) < 2 || rand.Float64() >= vGeneral.Foreign_cards {
+		return ""
+	}
+	for {
+		if card := c.codes[rand.Intn(len(c.codes))]; card != code {
+			return card
+		}
+	}
+}
+
+// Every currency used has a rate, and the rates and roundings make sense.
+func validateCurrencies(app types.Tp_general, seed types.TPSeed) []string {
+
+	var problems []string
+
+	known := make(map[string]bool)
+	for _, currency := range seed.Currencies {
+		if len(currency.Code) != 3 {
+			problems = append(problems, fmt.Sprintf("seed currency code must be ISO 4217, 3 letters, got %q", currency.Code))
+		} else if known[currency.Code] {
+			problems = append(problems, fmt.Sprintf("seed currency %s is duplicated", currency.Code))
+		}
+		known[currency.Code] = true
+
+		if currency.Rate <= 0 {
+			problems = append(problems, fmt.Sprintf("seed currency %s rate must be > 0, got %g", currency.Code, currency.Rate))
+		}
+		if currency.CashRounding < 0 {
+			problems = append(problems, fmt.Sprintf("seed currency %s cashRounding must be >= 0, got %g", currency.Code, currency.CashRounding))
+		}
+	}
+
+	if app.Currency != "" && len(seed.Currencies) > 0 && !known[app.Currency] {
+		problems = append(problems, fmt.Sprintf("app.Currency %s is not a seed currency", app.Currency))
+	}
+
+	for _, store := range seed.Stores {
+		if store.Currency == "" || store.Currency == app.Currency {
+			continue
+		}
+		if app.Currency == "" {
+			problems = append(problems, fmt.Sprintf("seed store %s (%s) currency %s needs an app.Currency, the currency of the seed prices", store.Id, store.Name, store.Currency))
+		} else if !known[store.Currency] {
+			problems = append(problems, fmt.Sprintf("seed store %s (%s) currency %s is not a seed currency", store.Id, store.Name, store.Currency))
+		}
+	}
+
+	if app.Foreign_cards < 0 || app.Foreign_cards > 1 {
+		problems = append(problems, fmt.Sprintf("app.Foreign_cards must be a fraction between 0 and 1, got %g", app.Foreign_cards))
+	}
+
+	return problems
+}
@@ -0,0 +1,185 @@
+/*****************************************************************************
+*
+*	File			: customers.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Loyalty customers. A pool of Customers is generated at startup, the same pool every run, each with
+*					: a loyalty tier and a home store. Customer_share of the baskets carry a customer, mostly one whose
+*					: home store it is, picked by tier so the higher tiers come back more often:
+*
+*					:	bronze		- 60% of the pool, 1 visit, 1x points
+*					:	silver		- 25% of the pool, 2 visits, 1.25x points
+*					:	gold		- 12% of the pool, 4 visits, 1.5x points
+*					:	platinum	-  3% of the pool, 8 visits, 2x points
+*
+*					: The payment earns Points_rate points per 1 spent (times the tier's multiplier), and Redeem_share
+*					: of the time redeems the customer's balance, at pointValue each, against the total, paid being
+*					: what remains. The balances are kept for the run only.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"cmd/types"
+
+	"github.com/brianvoe/gofakeit"
+)
+
+// Share of the customer baskets in the customer's home store
+const homeStoreShare = 0.8
+
+// What a loyalty point is worth when redeemed
+const pointValue = 0.10
+
+// gofakeit seed of the pool, so every run generates the same customers
+const customerSeed = 20261018
+
+type tpTier struct {
+	name   string
+	share  float64 // of the pool
+	visits float64 // relative visit frequency
+	points float64 // points multiplier
+}
+
+var tiers = []tpTier{
+	{"bronze", 0.60, 1, 1},
+	{"silver", 0.25, 2, 1.25},
+	{"gold", 0.12, 4, 1.5},
+	{"platinum", 0.03, 8, 2},
+}
+
+type tpCustomers struct {
+	pool     []*types.Customer
+	tier     []int            // tiers position per customer
+	balance  []int64          // points per customer
+	byId     map[string]int   // pool position per customer id
+	all      *tpPicker        // any customer, by visit frequency
+	home     map[string][]int // pool positions per home store id
+	homePick map[string]*tpPicker
+}
+
+func newCustomers(seed types.TPSeed) *tpCustomers {
+
+	c := &tpCustomers{
+		byId:     make(map[string]int),
+		home:     make(map[string][]int),
+		homePick: make(map[string]*tpPicker),
+	}
+	if vGeneral.Customers == 0 {
+		return c
+	}
+
+	var tierShares []float64
+	for _, t := range tiers {
+		tierShares = append(tierShares, t.share)
+	}
+	tierPick := newPicker(tierShares)
+
+	// The same customers every run, the baskets are reseeded as they are built.
+	gofakeit.Seed(customerSeed)
+
+	var visits []float64
+	for i := 0; i < vGeneral.Customers; i++ {
+		tier := tierPick.pick()
+		store := seed.Stores[varPick.stores.pick()]
+
+		customer := &types.Customer{
+			Id:          fmt.Sprintf("C%08d", i+1),
+			Name:        gofakeit.Name(),
+			LoyaltyTier: tiers[tier].name,
+			HomeStoreId: store.Id,
+			Email:       gofakeit.Email(),
+			Phone:       gofakeit.Phone(),
+		}
+
+		c.pool = append(c.pool, customer)
+		c.tier = append(c.tier, tier)
+		c.balance = append(c.balance, 0)
+		c.byId[customer.Id] = i
+		c.home[store.Id] = append(c.home[store.Id], i)
+		visits = append(visits, tiers[tier].visits)
+	}
+	c.all = newPicker(visits)
+
+	for storeId, positions := range c.home {
+		var w []float64
+		for _, i := range positions {
+			w = append(w, tiers[c.tier[i]].visits)
+		}
+		c.homePick[storeId] = newPicker(w)
+	}
+
+	return c
+}
+
+// The customer of a sale in the store, nil => an anonymous sale
+func (c *tpCustomers) pick(store types.TPStoreStruct) *types.Customer {
+
+	if len(c.pool) == 0 || rand.Float64() >= vGeneral.Customer_share {
+		return nil
+	}
+
+	if p, ok := c.homePick[store.Id]; ok && rand.Float64() < homeStoreShare {
+		return c.pool[c.home[store.Id][p.pick()]]
+	}
+	return c.pool[c.all.pick()]
+}
+
+// Redeem and earn points on a sale of total, returns the loyalty details for the payment.
+func (c *tpCustomers) settle(customerId string, total float64) (earned, redeemed int64, amount float64, balance int64) {
+
+	i, ok := c.byId[customerId]
+	if !ok {
+		return 0, 0, 0, 0
+	}
+
+	if c.balance[i] > 0 && rand.Float64() < vGeneral.Redeem_share {
+		redeemed = c.balance[i]
+		if limit := int64(total / pointValue); redeemed > limit {
+			redeemed = limit
+		}
+		amount = toFixed(float64(redeemed)*pointValue, 2)
+		c.balance[i] -= redeemed
+	}
+
+	rate := vGeneral.Points_rate
+	if rate == 0 {
+		rate = 1
+	}
+	earned = int64(math.Floor((total - amount) * rate * tiers[c.tier[i]].points))
+	c.balance[i] += earned
+
+	return earned, redeemed, amount, c.balance[i]
+}
+
+// Customers, Customer_share, Points_rate and Redeem_share make sense.
+func validateCustomers(app types.Tp_general) []string {
+
+	var problems []string
+
+	if app.Customers < 0 {
+		problems = append(problems, fmt.Sprintf("app.Customers must be >= 0 (0 => no customers), got %d", app.Customers))
+	}
+	if app.Customer_share < 0 || app.Customer_share > 1 {
+		problems = append(problems, fmt.Sprintf("app.Customer_share must be a fraction between 0 and 1, got %g", app.Customer_share))
+	}
+	if app.Customer_share > 0 && app.Customers == 0 {
+		problems = append(problems, "app.Customer_share needs a pool of app.Customers > 0")
+	}
+	if app.Points_rate < 0 {
+		problems = append(problems, fmt.Sprintf("app.Points_rate must be >= 0 (0 => 1 point per 1 spent), got %g", app.Points_rate))
+	}
+	if app.Redeem_share < 0 || app.Redeem_share > 1 {
+		problems = append(problems, fmt.Sprintf("app.Redeem_share must be a fraction between 0 and 1, got %g", app.Redeem_share))
+	}
+
+	return problems
+}
@@ -0,0 +1,217 @@
+/*****************************************************************************
+*
+*	File			: distribution.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: How stores, clerks and products are picked from the seed, as per *_app.json Distribution:
+*					:	uniform		- every store/clerk/product equally likely, the seed weights are ignored
+*					:	weighted	- as per the seed "weight" values, missing => 1 (default, uniform when no weights are given)
+*					:	zipf		- products ranked by seed weight (else seed order), the k'th product weighted 1/k^Skew
+*					:	pareto		- as zipf, the exponent solved so the top 20% of products take Skew of the sales, 0.8 => 80/20
+*
+*					: With zipf/pareto a handful of products dominate the sales, as in real retail, stores and clerks
+*					: follow their seed weights.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"cmd/types"
+)
+
+const (
+	distUniform  = "uniform"
+	distWeighted = "weighted"
+	distZipf     = "zipf"
+	distPareto   = "pareto"
+)
+
+// Default Skew per mode
+var distSkew = map[string]float64{distZipf: 1.07, distPareto: 0.8}
+
+// Picks a position in a slice of seed entries, as per their weights.
+type tpPicker struct {
+	cumulative []float64
+}
+
+func newPicker(weights []float64) *tpPicker {
+
+	p := &tpPicker{cumulative: make([]float64, len(weights))}
+
+	total := 0.0
+	for i, w := range weights {
+		total += w
+		p.cumulative[i] = total
+	}
+	return p
+}
+
+// Position of the next pick
+func (p *tpPicker) pick() int {
+
+	total := p.cumulative[len(p.cumulative)-1]
+	return sort.SearchFloat64s(p.cumulative, rand.Float64()*total)
+}
+
+// Share of the picks that will land on position i, used for the seed show output.
+func (p *tpPicker) share(i int) float64 {
+
+	total := p.cumulative[len(p.cumulative)-1]
+	if i == 0 {
+		return p.cumulative[0] / total
+	}
+	return (p.cumulative[i] - p.cumulative[i-1]) / total
+}
+
+// The seed weights, missing (0) => 1, all 1 when uniform.
+func seedWeights(seedWeights []float64, distribution string) []float64 {
+
+	weights := make([]float64, len(seedWeights))
+	for i, w := range seedWeights {
+		weights[i] = 1
+		if distribution != distUniform && w > 0 {
+			weights[i] = w
+		}
+	}
+	return weights
+}
+
+// Share of the total the top 20% of n ranks take, with weights 1/k^exponent
+func topShare(n int, exponent float64) float64 {
+
+	top := int(math.Ceil(float64(n) * 0.2))
+	total, topTotal := 0.0, 0.0
+	for k := 1; k <= n; k++ {
+		w := 1 / math.Pow(float64(k), exponent)
+		total += w
+		if k <= top {
+			topTotal += w
+		}
+	}
+	return topTotal / total
+}
+
+// The exponent for which the top 20% of n ranks take share of the total, bisection as topShare grows with the exponent.
+func paretoExponent(n int, share float64) float64 {
+
+	lo, hi := 0.0, 10.0
+	for i := 0; i < 60; i++ {
+		mid := (lo + hi) / 2
+		if topShare(n, mid) < share {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// Power law weights by rank, the rank being the seed weight order, highest first, ties in seed order.
+func rankWeights(seedWeights []float64, distribution string, skew float64) []float64 {
+
+	if skew == 0 {
+		skew = distSkew[distribution]
+	}
+	exponent := skew
+	if distribution == distPareto {
+		exponent = paretoExponent(len(seedWeights), skew)
+	}
+
+	order := make([]int, len(seedWeights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return seedWeights[order[a]] > seedWeights[order[b]] })
+
+	weights := make([]float64, len(seedWeights))
+	for rank, i := range order {
+		weights[i] = 1 / math.Pow(float64(rank+1), exponent)
+	}
+	return weights
+}
+
+type tpPickers struct {
+	stores   *tpPicker
+	products *tpPicker
+	clerks   map[string][]int // seed clerk positions per store id
+}
+
+// Build the store and product pickers, the clerks are picked per store, see roster.go for the seed, as per vGeneral.Distribution.
+func newPickers(seed types.TPSeed) tpPickers {
+
+	var storeW, productW []float64
+	for _, s := range seed.Stores {
+		storeW = append(storeW, s.Weight)
+	}
+	for _, p := range seed.Products {
+		productW = append(productW, p.Weight)
+	}
+
+	// The store profiles scale the stores' share of the traffic, see profiles.go
+	storeW = seedWeights(storeW, vGeneral.Distribution)
+	profiles := newProfiles(seed)
+	for i, s := range seed.Stores {
+		storeW[i] *= profiles.weight(s)
+	}
+
+	pickers := tpPickers{
+		stores:   newPicker(storeW),
+		products: newPicker(seedWeights(productW, vGeneral.Distribution)),
+		clerks:   make(map[string][]int),
+	}
+	for i, c := range seed.Clerks {
+		pickers.clerks[c.StoreId] = append(pickers.clerks[c.StoreId], i)
+	}
+
+	switch vGeneral.Distribution {
+	case distZipf, distPareto:
+		pickers.products = newPicker(rankWeights(productW, vGeneral.Distribution, vGeneral.Skew))
+	}
+
+	return pickers
+}
+
+// Checks on the Distribution settings and the seed weights.
+func validateDistribution(app types.Tp_general, seed types.TPSeed) []string {
+
+	var problems []string
+
+	switch app.Distribution {
+	case "", distUniform, distWeighted, distZipf, distPareto:
+	default:
+		problems = append(problems, fmt.Sprintf("app.Distribution must be %s, %s, %s or %s, got %q", distUniform, distWeighted, distZipf, distPareto, app.Distribution))
+	}
+	if app.Skew < 0 {
+		problems = append(problems, fmt.Sprintf("app.Skew must be >= 0 (0 => the %s/%s default), got %g", distZipf, distPareto, app.Skew))
+	}
+	if app.Distribution == distPareto && app.Skew != 0 && (app.Skew <= 0.2 || app.Skew >= 1) {
+		problems = append(problems, fmt.Sprintf("app.Skew is the share of the top 20%% of products with %s, between 0.2 and 1, ie 0.8, got %g", distPareto, app.Skew))
+	}
+
+	for _, s := range seed.Stores {
+		if s.Weight < 0 {
+			problems = append(problems, fmt.Sprintf("seed store %s weight must be >= 0, got %g", s.Id, s.Weight))
+		}
+	}
+	for _, c := range seed.Clerks {
+		if c.Weight < 0 {
+			problems = append(problems, fmt.Sprintf("seed clerk %s weight must be >= 0, got %g", c.Id, c.Weight))
+		}
+	}
+	for _, p := range seed.Products {
+		if p.Weight < 0 {
+			problems = append(problems, fmt.Sprintf("seed product %s weight must be >= 0, got %g", p.Id, p.Weight))
+		}
+	}
+
+	return problems
+}
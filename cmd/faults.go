@@ -0,0 +1,277 @@
+/*****************************************************************************
+*
+*	File			: faults.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Data-quality faults, Faults (*_app.json) of the sales get one of their basket or payment corrupted,
+*					: to exercise the error handling and dead letter queue of whatever consumes them, ie the Mongo sink
+*					: connector, see example/1. CreMongoSinks.txt. 0 => the data is clean, as before. Fault_kinds, comma
+*					: separated, limits the kinds, blank => all of them:
+*
+*					:	missing_field		- a required field left out, ie invoiceNumber, store or paid
+*					:	null_field			- a field set to null in the JSON
+*					:	wrong_type			- a field of the wrong JSON type, ie "total": "123.45" or "store": "Sandton"
+*					:	negative_quantity	- a basket line with a negative quantity
+*					:	bad_total			- a basket total or payment paid that does not add up
+*					:	bad_timestamp		- a malformed saleDateTime/saleTimestamp or payDateTime/payTimestamp
+*					:	undeserialisable	- on Kafka, a payload that is not in the Schema Registry wire format, the
+*					:						  JSON sinks get the document as is
+*
+*					: Kafka carries Protobuf, which has no nulls or types to get wrong, a null_field or wrong_type
+*					: document is posted to Kafka as its JSON, which does not deserialise either. The faults are
+*					: counted per kind, reported as the run ends.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"cmd/types"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	faultMissingField     = "missing_field"
+	faultNullField        = "null_field"
+	faultWrongType        = "wrong_type"
+	faultNegativeQuantity = "negative_quantity"
+	faultBadTotal         = "bad_total"
+	faultBadTimestamp     = "bad_timestamp"
+	faultUndeserialisable = "undeserialisable"
+
+	faultBasket  = "basket"
+	faultPayment = "payment"
+)
+
+var faultKinds = []string{faultMissingField, faultNullField, faultWrongType, faultNegativeQuantity, faultBadTotal, faultBadTimestamp, faultUndeserialisable}
+
+var badTimestamps = []string{"2026-13-45T25:61:00", "yesterday", "18/10/2026 14:05", "", "0000-00-00T00:00:00Z", "1760796309"}
+
+// The fault of a sale, kind blank => clean, on its basket or payment document
+type tpFault struct {
+	kind string
+	doc  string
+}
+
+type tpFaults struct {
+	share  float64
+	kinds  []string
+	counts map[string]int
+}
+
+func newFaults(app types.Tp_general) *tpFaults {
+
+	f := &tpFaults{share: app.Faults, counts: make(map[string]int)}
+	for _, kind := range strings.Split(app.Fault_kinds, ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			f.kinds = append(f.kinds, kind)
+		}
+	}
+	if len(f.kinds) == 0 {
+		f.kinds = faultKinds
+	}
+	return f
+}
+
+func (f *tpFaults) enabled() bool {
+	return f != nil && f.share > 0
+}
+
+// Roll the sale's fault, if any, and make the content faults on a copy of the basket or payment, the sale itself stays
+// as is for the orders, labels, manifest and Mongo upsert. Returns the fault and the basket and payment to post, the
+// copies, or if clean the sale's own. The faults only the JSON can carry are for corrupt, undeserialisable for the
+// Kafka sink.
+func (f *tpFaults) inject(sale_Basket *types.Pb_Basket, sale_Payment *types.Pb_Payment) (tpFault, *types.Pb_Basket, *types.Pb_Payment) {
+
+	if !f.enabled() || rand.Float64() >= f.share {
+		return tpFault{}, sale_Basket, sale_Payment
+	}
+
+	pb_Basket := proto.Clone(sale_Basket).(*types.Pb_Basket)
+	pb_Payment := proto.Clone(sale_Payment).(*types.Pb_Payment)
+
+	fault := tpFault{kind: f.kinds[rand.Intn(len(f.kinds))], doc: faultBasket}
+	if fault.kind != faultNegativeQuantity && rand.Intn(2) == 1 {
+		fault.doc = faultPayment
+	}
+	f.counts[fault.kind]++
+
+	switch fault.kind {
+	case faultMissingField:
+		// Zero values are left out of the JSON, omitempty, and of the Protobuf
+		if fault.doc == faultBasket {
+			[]func(){
+				func() { pb_Basket.InvoiceNumber = "" },
+				func() { pb_Basket.SaleDateTime = "" },
+				func() { pb_Basket.Store = nil },
+				func() { pb_Basket.BasketItems = nil },
+				func() { pb_Basket.Total = 0 },
+			}[rand.Intn(5)]()
+
+		} else {
+			[]func(){
+				func() { pb_Payment.InvoiceNumber = "" },
+				func() { pb_Payment.PayDateTime = "" },
+				func() { pb_Payment.Paid = 0 },
+				func() { pb_Payment.FinTransactionID = "" },
+			}[rand.Intn(4)]()
+
+		}
+
+	case faultNegativeQuantity:
+		if len(pb_Basket.BasketItems) > 0 {
+			item := pb_Basket.BasketItems[rand.Intn(len(pb_Basket.BasketItems))]
+			item.Quantity = -item.Quantity
+		}
+
+	case faultBadTotal:
+		off := toFixed(float64(1+rand.Intn(100))*(0.5+rand.Float64()), 2)
+		if fault.doc == faultBasket {
+			pb_Basket.Total = toFixed(pb_Basket.Total+off, 2)
+
+		} else {
+			pb_Payment.Paid = toFixed(pb_Payment.Paid-off, 2)
+
+		}
+
+	case faultBadTimestamp:
+		bad := badTimestamps[rand.Intn(len(badTimestamps))]
+		if fault.doc == faultBasket {
+			pb_Basket.SaleDateTime = bad
+			pb_Basket.SaleTimestamp = bad
+
+		} else {
+			pb_Payment.PayDateTime = bad
+			pb_Payment.PayTimestamp = bad
+
+		}
+	}
+
+	if vGeneral.Debuglevel >= 2 {
+		grpcLog.Infoln(fmt.Sprintf("Fault injected                : %s on the %s of %s", fault.kind, fault.doc, sale_Basket.InvoiceNumber))
+
+	}
+
+	return fault, pb_Basket, pb_Payment
+}
+
+// The JSON document, doc, with the fault's null_field or wrong_type, if the fault is on it.
+func (f tpFault) corrupt(doc string, json_Doc []byte) []byte {
+
+	if doc != f.doc || (f.kind != faultNullField && f.kind != faultWrongType) {
+		return json_Doc
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(json_Doc, &fields); err != nil || len(fields) == 0 {
+		return json_Doc
+	}
+
+	var keys []string
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	key := keys[rand.Intn(len(keys))]
+
+	if f.kind == faultNullField {
+		fields[key] = nil
+
+	} else {
+		switch v := fields[key].(type) {
+		case string:
+			fields[key] = rand.Intn(100000)
+		case float64:
+			fields[key] = fmt.Sprint(v)
+		case bool:
+			fields[key] = fmt.Sprint(v)
+		default:
+			// Objects and arrays become a string
+			b, _ := json.Marshal(v)
+			fields[key] = string(b)
+		}
+	}
+
+	corrupted, err := json.Marshal(fields)
+	if err != nil {
+		return json_Doc
+	}
+	return corrupted
+}
+
+// The Kafka value of the document, doc, if the fault is on it, nil => Protobuf serialize it as usual. Neither is in
+// the Schema Registry wire format, a magic byte and schema id ahead of the Protobuf.
+func (f tpFault) kafkaValue(doc string, json_Doc []byte) []byte {
+
+	if doc != f.doc {
+		return nil
+	}
+
+	switch f.kind {
+	case faultNullField, faultWrongType:
+		return json_Doc
+
+	case faultUndeserialisable:
+		switch rand.Intn(3) {
+		case 0:
+			// The wire format, of a schema id the registry does not have
+			return append([]byte{0, 0x7f, 0xff, 0xff, 0xff}, json_Doc...)
+		case 1:
+			// No magic byte, plain JSON
+			return json_Doc
+		default:
+			garbage := make([]byte, 16+rand.Intn(48))
+			rand.Read(garbage)
+			garbage[0] = 0xff
+			return garbage
+		}
+	}
+	return nil
+}
+
+// How many faults of each kind, for the end of the run.
+func (f *tpFaults) report() {
+
+	if !f.enabled() {
+		return
+	}
+
+	total := 0
+	for _, kind := range faultKinds {
+		total += f.counts[kind]
+	}
+	grpcLog.Infoln("Faults injected               : ", total)
+	for _, kind := range faultKinds {
+		if f.counts[kind] > 0 {
+			grpcLog.Infoln(fmt.Sprintf("  %-28s:  %d", kind, f.counts[kind]))
+		}
+	}
+}
+
+// Faults is a share, and the Fault_kinds are known.
+func validateFaults(app types.Tp_general) []string {
+
+	var problems []string
+
+	if app.Faults < 0 || app.Faults > 1 {
+		problems = append(problems, fmt.Sprintf("app.Faults must be a fraction between 0 and 1, got %g", app.Faults))
+	}
+
+	for _, kind := range strings.Split(app.Fault_kinds, ",") {
+		if kind = strings.TrimSpace(kind); kind != "" && !contains(faultKinds, kind) {
+			problems = append(problems, fmt.Sprintf("app.Fault_kinds must be of %s, got %q", strings.Join(faultKinds, ", "), kind))
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,159 @@
+/*****************************************************************************
+*
+*	File			: generate.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: seed generate, builds a seed file of any size, for scale testing:
+*
+*					: go run ./cmd seed generate --stores 300 --clerks 1500 --products 10000 --out scale_seed.json
+*
+*					: Stores get a street address, city, postal code and region, clerks are bound to the stores, every
+*					: store getting at least one, and products are drawn from a catalogue of categories, each with its
+*					: brands, items, sizes and price band. --random-seed makes the output repeatable.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"cmd/types"
+
+	"github.com/brianvoe/gofakeit"
+)
+
+// A category of the product catalogue, products are <brand> <variant> <item> <size>, priced in its band
+type tpCatalogue struct {
+	category    string
+	taxCategory string
+	brands      []string
+	items       []string
+	sizes       []string
+	low, high   float64 // price band
+}
+
+var catalogue = []tpCatalogue{
+	{"Food Cupboard", "", []string{"Knorr", "Koo", "Tastic", "Robertsons", "Hulett", "Fatti's & Moni's", "Kellogg's", "PnP"},
+		[]string{"Rice", "Baked Beans", "Spaghetti", "Cook In Sauce", "Brown Sugar", "Corn Flakes", "Sunflower Oil", "Soup"}, []string{"410g", "500g", "1kg", "2kg"}, 12, 120},
+	{"Beverage", "", []string{"Coca-Cola", "Pepsi", "Appletiser", "Liqui-Fruit", "Brookes", "Castle", "Nestle"},
+		[]string{"Soft Drink", "Sparkling Juice", "Fruit Juice", "Cordial", "Lager", "Iced Tea"}, []string{"330ml", "500ml", "1.5L", "2L", "6 Pack"}, 9, 180},
+	{"Coffee", "", []string{"Jacobs", "Nescafe", "Nespresso", "Frisco", "Ricoffy"},
+		[]string{"Instant Coffee", "Filter Coffee", "Coffee Beans", "Capsules", "Sachets"}, []string{"100g", "200g", "250g", "10 Pack"}, 35, 220},
+	{"Dairy", "zero", []string{"Clover", "Parmalat", "Douglasdale", "Danone", "Rama"},
+		[]string{"Milk", "Yoghurt", "Cheddar", "Butter", "Cream", "Spread"}, []string{"250ml", "500g", "1L", "2L"}, 8, 95},
+	{"Fresh Produce", "zero", []string{"PnP", "Farmer's Choice", "Fresh Farms"},
+		[]string{"Apples", "Bananas", "Potatoes", "Onions", "Tomatoes", "Carrots"}, []string{"500g", "1kg", "1.5kg", "2kg"}, 10, 70},
+	{"Cleaning", "", []string{"Domestos", "Sunlight", "Handy Andy", "Omo", "Dettol", "Jik"},
+		[]string{"Dishwashing Liquid", "Bleach Cleaner", "Washing Powder", "Multipurpose Cleaner", "Disinfectant"}, []string{"500ml", "750ml", "1L", "2kg"}, 18, 160},
+	{"Personal Health Care", "", []string{"Dove", "Colgate", "Nivea", "Vaseline", "Lifebuoy"},
+		[]string{"Soap", "Toothpaste", "Body Lotion", "Shampoo", "Deodorant", "Cotton Wool Rounds"}, []string{"90g", "100ml", "200ml", "400ml"}, 14, 130},
+	{"Snacks", "", []string{"Simba", "Cadbury", "Lay's", "Bakers", "Willards"},
+		[]string{"Potato Chips", "Chocolate Slab", "Biscuits", "Corn Chips", "Nuts"}, []string{"36g", "80g", "120g", "200g"}, 8, 60},
+	{"Pool Care", "", []string{"HTH", "Pool Brite"},
+		[]string{"Granular Chlorine", "Floater", "Clarifier", "Algaecide"}, []string{"1L", "1.6kg", "5kg", "8kg"}, 90, 950},
+	{"Stationary", "", []string{"PnP", "Bic", "Croxley"},
+		[]string{"Office Paper", "Ballpoint Pens", "Exercise Books", "Glue Stick"}, []string{"5 Pack", "10 Pack", "500 Sheets", "Single"}, 15, 450},
+}
+
+var variants = []string{"", "Original", "Lite", "Classic", "Extra", "Premium", "Family", "Value", "Organic", "Sugar Free"}
+
+var regions = []string{"Gauteng", "Western Cape", "KwaZulu-Natal", "Eastern Cape", "Free State", "Limpopo", "Mpumalanga", "North West", "Northern Cape"}
+
+// A seed of stores, clerks bound to them and products, as per the options.
+func generateSeed(stores int, clerks int, products int) types.TPSeed {
+
+	var seed types.TPSeed
+
+	names := make(map[string]int)
+	unique := func(name string) string {
+		names[name]++
+		if names[name] > 1 {
+			return fmt.Sprintf("%s %d", name, names[name])
+		}
+		return name
+	}
+
+	for i := 0; i < stores; i++ {
+		seed.Stores = append(seed.Stores, types.TPStoreStruct{
+			Id:         fmt.Sprintf("%09d", 100000001+i),
+			Name:       unique(gofakeit.City()),
+			Address:    gofakeit.Street(),
+			City:       gofakeit.City(),
+			PostalCode: gofakeit.Zip(),
+			Region:     regions[gofakeit.Number(0, len(regions)-1)],
+		})
+	}
+
+	// Every store gets a clerk, the rest are spread over the stores at random
+	for i := 0; i < clerks; i++ {
+		store := i
+		if i >= stores {
+			store = gofakeit.Number(0, stores-1)
+		}
+		seed.Clerks = append(seed.Clerks, types.TPClerkStruct{
+			Id:      fmt.Sprintf("%d", 10001+i),
+			Name:    gofakeit.FirstName(),
+			StoreId: seed.Stores[store].Id,
+		})
+	}
+
+	for i := 0; i < products; i++ {
+		c := catalogue[gofakeit.Number(0, len(catalogue)-1)]
+
+		brand := c.brands[gofakeit.Number(0, len(c.brands)-1)]
+		words := []string{brand, variants[gofakeit.Number(0, len(variants)-1)], c.items[gofakeit.Number(0, len(c.items)-1)], c.sizes[gofakeit.Number(0, len(c.sizes)-1)]}
+
+		// Retail prices end in .99
+		price := math.Floor(gofakeit.Price(c.low, c.high)) + 0.99
+
+		seed.Products = append(seed.Products, types.TProductStruct{
+			Id:          fmt.Sprintf("%09d", i+1),
+			Name:        unique(strings.Join(strings.Fields(strings.Join(words, " ")), " ")),
+			Brand:       brand,
+			Category:    c.category,
+			Price:       toFixed(price, 2),
+			TaxCategory: c.taxCategory,
+		})
+	}
+
+	return seed
+}
+
+// seed generate, writes the generated seed to opts.out
+func runSeedGenerate(opts *tpOptions) {
+
+	if opts.stores < 1 || opts.clerks < opts.stores || opts.products < 1 {
+		grpcLog.Fatalln(fmt.Sprintf("seed generate needs --stores >= 1, --clerks >= --stores and --products >= 1, got %d, %d and %d", opts.stores, opts.clerks, opts.products))
+
+	}
+
+	// 0 => a different seed every time
+	gofakeit.Seed(opts.randomSeed)
+
+	seed := generateSeed(opts.stores, opts.clerks, opts.products)
+
+	v, err := json.MarshalIndent(seed, "", "    ")
+	if err != nil {
+		grpcLog.Fatalln("Marchalling error: ", err)
+
+	}
+
+	if err := os.WriteFile(opts.out, append(v, '\n'), 0644); err != nil {
+		grpcLog.Fatalln("Error Writing Seed File: ", err)
+
+	}
+
+	grpcLog.Infoln("* Seed File                   :", opts.out)
+	grpcLog.Infoln("* Stores                      :", len(seed.Stores))
+	grpcLog.Infoln("* Clerks                      :", len(seed.Clerks))
+	grpcLog.Infoln("* Products                    :", len(seed.Products))
+
+}
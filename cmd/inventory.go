@@ -0,0 +1,336 @@
+/*****************************************************************************
+*
+*	File			: inventory.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Stock per store and product, from the seed "inventory", without one stock is endless, as before:
+*
+*					: "inventory": {"opening": 200, "levels": {"000000001": 500},
+*					:               "deliveries": [{"days": ["mon", "thu"], "at": "06:00"}],
+*					:               "stockOut": "substitute"}
+*
+*					: Every store opens with its products' level, levels, else opening, in stock. Each sale takes its
+*					: quantity off the shelf, the scheduled deliveries top the shelf back up to the level, in the store's
+*					: local time. An item that is out of stock is dropped from the basket, or with stockOut substitute,
+*					: swapped for another product of its category that is in stock, a short item is sold short.
+*
+*					: Every movement, opening, delivery, sale, stock_out (the demand that could not be sold) and return (a
+*					: refund, see anomalies.go), is an inventory movement event, posted to InventoryTopicname / Inventorycollection and the json_save
+*					: inventory file, next to the baskets.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"cmd/types"
+)
+
+const (
+	stockDrop       = "drop"
+	stockSubstitute = "substitute"
+
+	moveOpening  = "opening"
+	moveDelivery = "delivery"
+	moveSale     = "sale"
+	moveStockOut = "stock_out"
+	moveReturn   = "return"
+)
+
+type tpInventory struct {
+	level      map[string]int            // product id => stock a store opens with and deliveries top up to, absent => endless
+	onHand     map[string]map[string]int // store id => product id => stock, absent => not opened yet
+	delivered  map[string]time.Time      // store id => up to when its deliveries have been made
+	byCategory map[string][]int          // product category => seed positions
+	byId       map[string]int            // product id => seed position
+	movements  []*types.PBInventoryMovement
+}
+
+func newInventory(seed types.TPSeed) *tpInventory {
+
+	i := &tpInventory{
+		level:      make(map[string]int),
+		onHand:     make(map[string]map[string]int),
+		delivered:  make(map[string]time.Time),
+		byCategory: make(map[string][]int),
+		byId:       make(map[string]int),
+	}
+
+	for p, product := range seed.Products {
+		if level, ok := seed.Inventory.Levels[product.Id]; ok {
+			i.level[product.Id] = level
+
+		} else if seed.Inventory.Opening > 0 {
+			i.level[product.Id] = seed.Inventory.Opening
+
+		}
+		i.byCategory[product.Category] = append(i.byCategory[product.Category], p)
+		i.byId[product.Id] = p
+	}
+	return i
+}
+
+// The inventory for a refreshed seed, the stock on hand, deliveries made and movements not yet posted carry over.
+func (i *tpInventory) reseed(seed types.TPSeed) *tpInventory {
+
+	fresh := newInventory(seed)
+	fresh.onHand = i.onHand
+	fresh.delivered = i.delivered
+	fresh.movements = i.movements
+	return fresh
+}
+
+func (i *tpInventory) enabled() bool {
+	return len(i.level) > 0
+}
+
+// Record a movement of quantity, negative => out, of the product in the store at t, store local time.
+func (i *tpInventory) move(store types.TPStoreStruct, product types.TProductStruct, kind string, quantity int, demand int, invoiceNumber string, substituteFor string, t time.Time) {
+
+	shelf := i.onHand[store.Id]
+	shelf[product.Id] += quantity
+
+	i.movements = append(i.movements, &types.PBInventoryMovement{
+		MovementId:        uuid.New().String(),
+		MovementDateTime:  formatTimestamp(t),
+		MovementTimestamp: fmt.Sprint(t.UnixMilli()),
+		StoreId:           store.Id,
+		StoreName:         store.Name,
+		ProductId:         product.Id,
+		ProductName:       product.Name,
+		Category:          product.Category,
+		MovementType:      kind,
+		Quantity:          int32(quantity),
+		OnHand:            int32(shelf[product.Id]),
+		Demand:            int32(demand),
+		InvoiceNumber:     invoiceNumber,
+		SubstituteFor:     substituteFor,
+	})
+}
+
+// The product's stock in the store, opening it at t if this is the first we see of it, false => endless.
+func (i *tpInventory) stock(store types.TPStoreStruct, product types.TProductStruct, t time.Time) (int, bool) {
+
+	level, ok := i.level[product.Id]
+	if !ok {
+		return 0, false
+	}
+
+	if i.onHand[store.Id] == nil {
+		i.onHand[store.Id] = make(map[string]int)
+	}
+	if _, ok := i.onHand[store.Id][product.Id]; !ok {
+		i.onHand[store.Id][product.Id] = 0
+		i.move(store, product, moveOpening, level, 0, "", "", t)
+	}
+	return i.onHand[store.Id][product.Id], true
+}
+
+// The last scheduled delivery after from, up to and including to, both store local time.
+func lastDelivery(deliveries []types.TPDelivery, from time.Time, to time.Time) (time.Time, bool) {
+
+	// A week back is far enough, a delivery tops up to the level whatever happened before it
+	for day := 0; day < 8; day++ {
+		d := to.AddDate(0, 0, -day)
+
+		var last time.Time
+		for _, delivery := range deliveries {
+			at, _ := shiftMinutes(delivery.At)
+			m := time.Date(d.Year(), d.Month(), d.Day(), at/60, at%60, 0, 0, to.Location())
+			if onDay(delivery.Days, d.Weekday()) && m.After(from) && !m.After(to) && m.After(last) {
+				last = m
+			}
+		}
+		if !last.IsZero() {
+			return last, true
+		}
+		if d.Before(from) {
+			break
+		}
+	}
+	return time.Time{}, false
+}
+
+// Make the store's deliveries due by t, store local time, topping the products it has opened up to their level.
+func (i *tpInventory) deliver(seed types.TPSeed, store types.TPStoreStruct, t time.Time) {
+
+	if !i.enabled() {
+		return
+	}
+
+	from, ok := i.delivered[store.Id]
+	i.delivered[store.Id] = t
+	if !ok {
+		return
+	}
+
+	at, ok := lastDelivery(seed.Inventory.Deliveries, from, t)
+	if !ok {
+		return
+	}
+
+	for _, product := range seed.Products {
+		onHand, opened := i.onHand[store.Id][product.Id]
+		if level, stocked := i.level[product.Id]; opened && stocked && onHand < level {
+			i.move(store, product, moveDelivery, level-onHand, 0, "", "", at)
+		}
+	}
+}
+
+// Take quantity of the product off the store's shelf at t, for the basket invoiceNumber, as much of it as there is.
+// Returns the quantity actually sold.
+func (i *tpInventory) sell(seed types.TPSeed, store types.TPStoreStruct, product int, quantity int, invoiceNumber string, t time.Time) int {
+
+	onHand, stocked := i.stock(store, seed.Products[product], t)
+	if !stocked {
+		return quantity
+	}
+
+	sold := quantity
+	if onHand < sold {
+		sold = onHand
+	}
+	if sold > 0 {
+		i.move(store, seed.Products[product], moveSale, -sold, quantity, invoiceNumber, "", t)
+	}
+	if sold < quantity {
+		i.move(store, seed.Products[product], moveStockOut, 0, quantity-sold, invoiceNumber, "", t)
+	}
+	return sold
+}
+
+// Take quantity of the product off the store's shelf at t, for the basket invoiceNumber. Returns the product and the
+// quantity actually sold, a substitute as per the seed stockOut, 0 => out of stock, drop the item.
+func (i *tpInventory) take(seed types.TPSeed, store types.TPStoreStruct, product int, quantity int, invoiceNumber string, t time.Time) (int, int) {
+
+	if sold := i.sell(seed, store, product, quantity, invoiceNumber, t); sold > 0 {
+		return product, sold
+	}
+
+	if seed.Inventory.StockOut != stockSubstitute {
+		return product, 0
+	}
+
+	// Another product of the category that is in stock
+	var candidates []int
+	for _, p := range i.byCategory[seed.Products[product].Category] {
+		if onHand, stocked := i.stock(store, seed.Products[p], t); p != product && (!stocked || onHand > 0) {
+			candidates = append(candidates, p)
+		}
+	}
+	if len(candidates) == 0 {
+		return product, 0
+	}
+
+	substitute := candidates[rand.Intn(len(candidates))]
+	sold := quantity
+	if onHand, stocked := i.stock(store, seed.Products[substitute], t); stocked {
+		if onHand < sold {
+			sold = onHand
+		}
+		i.move(store, seed.Products[substitute], moveSale, -sold, quantity, invoiceNumber, seed.Products[product].Id, t)
+	}
+	return substitute, sold
+}
+
+// Put the quantity of the product, by id, back on the store's shelf at t, refunded on the basket invoiceNumber.
+func (i *tpInventory) restock(seed types.TPSeed, store types.TPStoreStruct, productId string, quantity int, invoiceNumber string, t time.Time) {
+
+	product, ok := i.byId[productId]
+	if !ok || quantity <= 0 {
+		return
+	}
+	if _, stocked := i.stock(store, seed.Products[product], t); stocked {
+		i.move(store, seed.Products[product], moveReturn, quantity, 0, invoiceNumber, "", t)
+	}
+}
+
+// Take the basket invoiceNumber's sales back off the movements not yet drained, its stock back on the shelf, for a
+// basket that is not posted after all, see anomalies.go.
+func (i *tpInventory) undo(invoiceNumber string) {
+
+	kept := i.movements[:0]
+	for _, movement := range i.movements {
+		if movement.InvoiceNumber != invoiceNumber {
+			kept = append(kept, movement)
+			continue
+		}
+		i.onHand[movement.StoreId][movement.ProductId] -= int(movement.Quantity)
+	}
+	i.movements = kept
+}
+
+// The seed position of the product id, false => not a seed product.
+func (i *tpInventory) product(productId string) (int, bool) {
+
+	product, ok := i.byId[productId]
+	return product, ok
+}
+
+// The movements since the last drain, for the sinks.
+func (i *tpInventory) drain() []*types.PBInventoryMovement {
+
+	movements := i.movements
+	i.movements = nil
+	return movements
+}
+
+// Stock levels are positive and of seed products, the deliveries are on days at HH:MM, and the stockOut is known.
+func validateInventory(seed types.TPSeed) []string {
+
+	var problems []string
+
+	inventory := seed.Inventory
+	if inventory.Opening < 0 {
+		problems = append(problems, fmt.Sprintf("seed inventory opening must be >= 0, got %d", inventory.Opening))
+	}
+
+	products := make(map[string]bool)
+	for _, product := range seed.Products {
+		products[product.Id] = true
+	}
+
+	var ids []string
+	for id := range inventory.Levels {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if !products[id] {
+			problems = append(problems, fmt.Sprintf("seed inventory levels product %s is not a seed product", id))
+		}
+		if inventory.Levels[id] < 0 {
+			problems = append(problems, fmt.Sprintf("seed inventory levels product %s must be >= 0, got %d", id, inventory.Levels[id]))
+		}
+	}
+
+	for _, delivery := range inventory.Deliveries {
+		if _, err := shiftMinutes(delivery.At); err != nil {
+			problems = append(problems, fmt.Sprintf("seed inventory delivery at must be HH:MM, got %q", delivery.At))
+		}
+		for _, d := range delivery.Days {
+			if _, ok := weekdays[strings.ToLower(d)]; !ok {
+				problems = append(problems, fmt.Sprintf("seed inventory delivery day must be sun, mon, tue, wed, thu, fri or sat, got %q", d))
+			}
+		}
+	}
+
+	switch inventory.StockOut {
+	case "", stockDrop, stockSubstitute:
+	default:
+		problems = append(problems, fmt.Sprintf("seed inventory stockOut must be %s or %s, got %q", stockDrop, stockSubstitute, inventory.StockOut))
+	}
+
+	return problems
+}
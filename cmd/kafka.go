@@ -0,0 +1,264 @@
+/*****************************************************************************
+*
+*	File			: kafka.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Confluent Kafka producer, Protobuf serialized via the Schema Registry. Moved out of runLoader
+*					: so that produce and replay (and whatever else needs to post) share the one producer.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/confluentinc/confluent-kafka-go/schemaregistry"
+	"github.com/confluentinc/confluent-kafka-go/schemaregistry/serde/protobuf"
+
+	"cmd/types"
+)
+
+type kafkaSink struct {
+	p          *kafka.Producer
+	serializer *protobuf.Serializer
+	vFlush     int       // posts since we last flushed the queue
+	termChan   chan bool // For signalling termination from main to go-routine
+	doneChan   chan bool // For signalling that termination is done from go-routine to main
+}
+
+// Create Producer instance
+// https://docs.confluent.io/current/clients/confluent-kafka-go/index.html#NewProducer
+func newKafkaSink() (*kafkaSink, error) {
+
+	var err error
+
+	k := &kafkaSink{
+		termChan: make(chan bool, 1),
+		doneChan: make(chan bool),
+	}
+
+	if vGeneral.Debuglevel > 0 {
+		grpcLog.Info("**** Configure Client Kafka Connection ****")
+		grpcLog.Info("*")
+		grpcLog.Info(fmt.Sprintf("* Kafka bootstrap Server is %s", vKafka.Bootstrapservers))
+		if vKafka.SchemaRegistryURL != "" {
+			grpcLog.Info(fmt.Sprintf("* Schema Registry URL is    %s", vKafka.SchemaRegistryURL))
+		}
+	}
+
+	cm := kafka.ConfigMap{
+		"bootstrap.servers":       vKafka.Bootstrapservers,
+		"broker.version.fallback": "0.10.0.0",
+		"api.version.fallback.ms": 0,
+		"client.id":               vGeneral.Hostname,
+	}
+
+	if vGeneral.Debuglevel > 0 {
+		grpcLog.Info("* Basic Client ConfigMap compiled")
+
+	}
+
+	if vKafka.Sasl_mechanisms != "" {
+		cm["sasl.mechanisms"] = vKafka.Sasl_mechanisms
+		cm["security.protocol"] = vKafka.Security_protocol
+		cm["sasl.username"] = vKafka.Sasl_username
+		cm["sasl.password"] = vKafka.Sasl_password
+		if vGeneral.Debuglevel > 0 {
+			grpcLog.Info("* Security Authentifaction configured in ConfigMap")
+			grpcLog.Info("* Mechanism ", vKafka.Sasl_mechanisms, " Username ", vKafka.Sasl_username)
+
+		}
+	}
+
+	// Variable p holds the new Producer instance.
+	k.p, err = kafka.NewProducer(&cm)
+
+	// Check for errors in creating the Producer
+	if err != nil {
+		grpcLog.Error(fmt.Sprintf("😢Oh noes, there's an error creating the Producer! %s", err))
+
+		if ke, ok := err.(kafka.Error); ok {
+			switch ec := ke.Code(); ec {
+			case kafka.ErrInvalidArg:
+				grpcLog.Error(fmt.Sprintf("😢 Can't create the producer because you've configured it wrong (code: %d)!\n\t%v\n\nTo see the configuration options, refer to https://github.com/edenhill/librdkafka/blob/master/CONFIGURATION.md", ec, err))
+			default:
+				grpcLog.Error(fmt.Sprintf("😢 Can't create the producer (Kafka error code %d)\n\tError: %v\n", ec, err))
+			}
+
+		} else {
+			// It's not a kafka.Error
+			grpcLog.Error(fmt.Sprintf("😢 Oh noes, there's a generic error creating the Producer! %v", err.Error()))
+		}
+		return nil, err
+
+	}
+
+	// Create a new Schema Registry client
+	client, err := schemaregistry.NewClient(schemaregistry.NewConfig(vKafka.SchemaRegistryURL))
+	if err != nil {
+		k.p.Close()
+		return nil, fmt.Errorf("failed to create Schema Registry client: %w", err)
+
+	}
+
+	serdeConfig := protobuf.NewSerializerConfig()
+	//serdeConfig.AutoRegisterSchemas = false
+	//serdeConfig.UseLatestVersion = true
+	//serdeConfig.EnableValidation = true
+
+	// Create a Protobuf serializer
+	k.serializer, err = protobuf.NewSerializer(client, 2, serdeConfig)
+	if err != nil {
+		k.p.Close()
+		return nil, fmt.Errorf("failed to create Protobuf serializer: %w", err)
+
+	}
+
+	// Convenient way to Handle any events (back chatter) that we get
+	go k.events()
+
+	if vGeneral.Debuglevel > 0 {
+		grpcLog.Infoln("* Created Kafka Producer instance :")
+		grpcLog.Infoln("")
+	}
+
+	return k, nil
+}
+
+func (k *kafkaSink) events() {
+
+	doTerm := false
+	for !doTerm {
+		// The `select` blocks until one of the `case` conditions
+		// are met - therefore we run it in a Go Routine.
+		select {
+		case ev := <-k.p.Events():
+			// Look at the type of Event we've received
+			switch em := ev.(type) {
+
+			case *kafka.Message:
+				// It's a delivery report
+				if em.TopicPartition.Error != nil {
+					grpcLog.Error(fmt.Sprintf("☠️ Failed to send message to topic '%v'\tErr: %v",
+						string(*em.TopicPartition.Topic),
+						em.TopicPartition.Error))
+
+				} else {
+					if vGeneral.Debuglevel > 2 {
+						grpcLog.Info(fmt.Sprintf("✅ Message delivered to topic '%v'(partition %d at offset %d)",
+							string(*em.TopicPartition.Topic),
+							em.TopicPartition.Partition,
+							em.TopicPartition.Offset))
+
+					}
+				}
+
+			case kafka.Error:
+				// It's an error
+				grpcLog.Error(fmt.Sprintf("☠️ Uh oh, caught an error:\n\t%v", em))
+
+			}
+		case <-k.termChan:
+			doTerm = true
+
+		}
+	}
+	close(k.doneChan)
+}
+
+// Serialize msg as per the topic's schema and publish it, keyed by key.
+func (k *kafkaSink) produce(topic string, key string, msg interface{}) error {
+
+	valueBytes, err := k.serializer.Serialize(topic, msg)
+	if err != nil {
+		return fmt.Errorf("failed to serialize record for %s: %w", topic, err)
+	}
+
+	k.send(topic, key, valueBytes)
+	return nil
+}
+
+// Post an already serialized value, or a faulty one, see faults.go, onto the topic.
+func (k *kafkaSink) send(topic string, key string, valueBytes []byte) {
+
+	kafkaMsg := kafka.Message{
+		TopicPartition: kafka.TopicPartition{
+			Topic:     &topic,
+			Partition: kafka.PartitionAny,
+		},
+		Value: valueBytes,  // This is the payload/body thats being posted
+		Key:   []byte(key), // We us this to group the same transactions together in order, IE submitting/Debtor Bank.
+	}
+
+	// This is where we publish message onto the topic... on the Confluent cluster for now,
+	if err := k.p.Produce(&kafkaMsg, nil); err != nil {
+		grpcLog.Error(fmt.Sprintf("😢 Darn, there's an error producing the message! %s", err.Error()))
+
+	}
+}
+
+// Post the basket and its payment onto their topics, keyed by store name.
+func (k *kafkaSink) postSale(pb_Basket *types.Pb_Basket, pb_Payment *types.Pb_Payment, fault tpFault, json_SalesBasket []byte, json_Payment []byte) {
+
+	if vGeneral.Debuglevel >= 2 {
+		grpcLog.Info("")
+		grpcLog.Info("Post to Confluent Kafka topics")
+	}
+
+	storeName := pb_Basket.Store.GetName()
+
+	// Proto serialize SalesBasket, unless it is faulty
+	if value := fault.kafkaValue(faultBasket, json_SalesBasket); value != nil {
+		k.send(vKafka.BasketTopicname, storeName, value)
+
+	} else if err := k.produce(vKafka.BasketTopicname, storeName, pb_Basket); err != nil {
+		grpcLog.Fatalf("Basket: %s", err)
+	}
+
+	// Proto serialize SalesPayment, unless it is faulty
+	if value := fault.kafkaValue(faultPayment, json_Payment); value != nil {
+		k.send(vKafka.PaymentTopicname, storeName, value)
+
+	} else if err := k.produce(vKafka.PaymentTopicname, storeName, pb_Payment); err != nil {
+		grpcLog.Fatalf("Payment: %s", err)
+	}
+
+	k.vFlush++
+
+	// Fush every flush_interval loops
+	if k.vFlush >= vKafka.Flush_interval {
+		k.flush()
+	}
+}
+
+func (k *kafkaSink) flush() {
+
+	t := 10000
+	if r := k.p.Flush(t); r > 0 {
+		grpcLog.Error(fmt.Sprintf("Failed to flush all messages after %d milliseconds. %d message(s) remain", t, r))
+
+	} else {
+		if vGeneral.Debuglevel >= 1 {
+			grpcLog.Info(fmt.Sprintf("%d, Messages flushed from the queue", k.vFlush))
+
+		}
+		k.vFlush = 0
+	}
+}
+
+// Flush what is still queued, stop the events go-routine and close the producer.
+func (k *kafkaSink) close() {
+
+	k.flush()
+
+	k.termChan <- true
+	<-k.doneChan
+
+	k.p.Close()
+
+}
@@ -0,0 +1,385 @@
+/*****************************************************************************
+*
+*	File			: latency.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: End-to-end latency, producer -> Kafka -> Mongo sink connector -> collection.
+*					: The producer stamps every basket and payment with produceTimestamp (epoch microseconds) as it
+*					: is handed to the sinks, here we watch the Datastore change stream for those documents arriving
+*					: and take the change event wallTime (MongoDB 6.0+, else our receive time) less produceTimestamp.
+*
+*					: go run ./cmd latency --env <env>
+*
+*					: Runs for Latency_watch seconds, 0 => until Ctrl-C, then prints the percentiles per collection.
+*					: If Prom_pushgateway is set the latencies are also pushed there every 10 seconds.
+*
+*					: With Latency = 1 produce, backfill and scenario watch the same way while they run, and once done,
+*					: and the documents stopped arriving for latencySettle, report the percentiles in the run summary.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var latencyQuantiles = []float64{0.5, 0.9, 0.95, 0.99}
+
+// How long the documents have to stop arriving, after a run, before we report its latency
+const latencySettle = 10 * time.Second
+
+type tpLatency struct {
+	mu      sync.Mutex
+	samples map[string][]float64 // milliseconds, per collection
+	last    time.Time            // when the latest document arrived
+}
+
+func (l *tpLatency) add(colName string, ms float64) {
+	l.mu.Lock()
+	l.samples[colName] = append(l.samples[colName], ms)
+	l.last = time.Now()
+	l.mu.Unlock()
+}
+
+func (l *tpLatency) arrived() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.last
+}
+
+// Nearest rank percentile of an already sorted slice
+func percentile(sorted []float64, q float64) float64 {
+
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	idx := int(q*float64(len(sorted))+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// Sorted copy of the samples per collection
+func (l *tpLatency) sorted() map[string][]float64 {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	result := make(map[string][]float64)
+	for colName, samples := range l.samples {
+		s := append([]float64(nil), samples...)
+		sort.Float64s(s)
+		result[colName] = s
+	}
+	return result
+}
+
+// produceTimestamp as written, int64 from the sink connector, but could be int32/double via the JSON inserts.
+func epochMicros(v interface{}) (int64, bool) {
+
+	switch n := v.(type) {
+	case int64:
+		return n, n > 0
+	case int32:
+		return int64(n), n > 0
+	case float64:
+		return int64(n), n > 0
+	}
+	return 0, false
+}
+
+// The collections we expect the produced documents to land in, as per the document Modelling.
+func latencyCollections() bson.A {
+
+	switch vMongodb.Modelling {
+	case modelEmbedded:
+		return bson.A{vMongodb.Salescollection}
+	case modelUpsert:
+		return bson.A{vMongodb.Basketcollection}
+	}
+	return bson.A{vMongodb.Basketcollection, vMongodb.Paymentcollection}
+}
+
+func printLatency(latency *tpLatency) {
+
+	for colName, s := range latency.sorted() {
+		sum := 0.0
+		for _, ms := range s {
+			sum += ms
+		}
+
+		grpcLog.Infoln("")
+		grpcLog.Infoln("****** Collection", colName, "*****")
+		grpcLog.Infoln("* Documents                   :", len(s))
+		grpcLog.Infoln(fmt.Sprintf("* Mean                        : %.3f ms", sum/float64(len(s))))
+		for _, q := range latencyQuantiles {
+			grpcLog.Infoln(fmt.Sprintf("* p%-27g: %.3f ms", q*100, percentile(s, q)))
+		}
+		grpcLog.Infoln(fmt.Sprintf("* Max                         : %.3f ms", s[len(s)-1]))
+		grpcLog.Infoln("*")
+	}
+}
+
+// A change stream watch of the produced documents arriving, for the latency command and, Latency = 1, the runs.
+type tpLatencyWatch struct {
+	latency   *tpLatency
+	histogram *prometheus.HistogramVec
+	quantiles *prometheus.GaugeVec
+	pusher    *push.Pusher
+}
+
+func newLatencyWatch() *tpLatencyWatch {
+
+	w := &tpLatencyWatch{
+		latency: &tpLatency{samples: make(map[string][]float64)},
+		histogram: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "mongocreator_e2e_latency_seconds",
+			Help:    "Producer to Mongo collection latency of the generated documents.",
+			Buckets: prometheus.ExponentialBuckets(0.005, 2, 14),
+		}, []string{"collection"}),
+		quantiles: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "mongocreator_e2e_latency_quantile_seconds",
+			Help: "Producer to Mongo collection latency percentiles of the generated documents.",
+		}, []string{"collection", "quantile"}),
+	}
+
+	if vGeneral.Prom_pushgateway != "" {
+		w.pusher = push.New(vGeneral.Prom_pushgateway, "mongocreator_latency").
+			Grouping("instance", vGeneral.Hostname).
+			Collector(w.histogram).
+			Collector(w.quantiles)
+	}
+	return w
+}
+
+// Push the latency histogram and percentiles, if we have a Prom_pushgateway.
+func (w *tpLatencyWatch) push() {
+
+	if w.pusher == nil {
+		return
+	}
+	for colName, s := range w.latency.sorted() {
+		for _, q := range latencyQuantiles {
+			w.quantiles.WithLabelValues(colName, fmt.Sprint(q)).Set(percentile(s, q) / 1000)
+		}
+	}
+	if err := w.pusher.Push(); err != nil {
+		grpcLog.Errorln("Prometheus push failed: ", err)
+	}
+}
+
+// Open the change stream on the collections, as per the document Modelling.
+func (w *tpLatencyWatch) open(ctx context.Context, appLabDatabase *mongo.Database) (*mongo.ChangeStream, error) {
+
+	pipeline := bson.A{bson.M{"$match": bson.M{
+		"operationType": bson.M{"$in": bson.A{"insert", "update", "replace"}},
+		"ns.coll":       bson.M{"$in": latencyCollections()},
+	}}}
+
+	// Change streams needs a replica set, there is no sensible fallback for latency.
+	return appLabDatabase.Watch(ctx, pipeline, options.ChangeStream().SetFullDocument(options.UpdateLookup))
+}
+
+// Measure the documents arriving, until ctx is done, pushing the metrics every 10 seconds.
+func (w *tpLatencyWatch) follow(ctx context.Context, stream *mongo.ChangeStream) {
+
+	defer stream.Close(context.TODO())
+
+	go func() {
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				w.push()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for stream.Next(ctx) {
+		var event struct {
+			OperationType string    `bson:"operationType"`
+			WallTime      time.Time `bson:"wallTime"`
+			Ns            struct {
+				Coll string `bson:"coll"`
+			} `bson:"ns"`
+			FullDocument bson.M `bson:"fullDocument"`
+		}
+
+		received := time.Now()
+		if err := stream.Decode(&event); err != nil {
+			grpcLog.Errorln("Change event decode failed: ", err)
+			continue
+		}
+
+		arrived := event.WallTime
+		if arrived.IsZero() {
+			arrived = received
+		}
+
+		// An update is the payment being upserted into its basket, otherwise the document itself.
+		stamp := event.FullDocument["produceTimestamp"]
+		if event.OperationType == "update" {
+			stamp = docValue(event.FullDocument, "payment.produceTimestamp")
+		}
+
+		micros, ok := epochMicros(stamp)
+		if !ok {
+			continue
+		}
+
+		elapsed := arrived.Sub(time.UnixMicro(micros))
+		w.latency.add(event.Ns.Coll, float64(elapsed.Microseconds())/1000)
+		w.histogram.WithLabelValues(event.Ns.Coll).Observe(elapsed.Seconds())
+
+		if vGeneral.Debuglevel > 1 {
+			grpcLog.Infoln(fmt.Sprintf("%-30s: %.3f ms", event.Ns.Coll, float64(elapsed.Microseconds())/1000))
+
+		}
+	}
+}
+
+// Watch the Datastore for produced documents arriving and measure their end-to-end latency.
+func runLatency(opts *tpOptions) {
+
+	loadSettings(opts, false, true)
+
+	Mongoclient, err := connectMongo(vMongodb)
+	if err != nil {
+		grpcLog.Fatal("Mongo Connect Failed: ", err)
+	}
+	defer Mongoclient.Disconnect(context.TODO())
+
+	appLabDatabase := Mongoclient.Database(vMongodb.Datastore)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if vGeneral.Latency_watch > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(vGeneral.Latency_watch)*time.Second)
+		defer cancel()
+	}
+
+	w := newLatencyWatch()
+	stream, err := w.open(ctx, appLabDatabase)
+	if err != nil {
+		grpcLog.Fatal("Change Stream Watch Failed: ", err)
+	}
+
+	grpcLog.Infoln("*")
+	grpcLog.Infoln("* Watching Datastore          :", vMongodb.Datastore)
+	grpcLog.Infoln("* Collections                 :", latencyCollections())
+	grpcLog.Infoln("* Watch (Seconds)             :", vGeneral.Latency_watch)
+	grpcLog.Infoln("*")
+
+	vStart := time.Now()
+	w.follow(ctx, stream)
+
+	grpcLog.Infoln("")
+	grpcLog.Infoln("**** DONE Watching ****")
+	grpcLog.Infoln("Elapsed Time (Seconds)        : ", time.Since(vStart).Seconds())
+
+	printLatency(w.latency)
+	w.push()
+
+}
+
+// The latency of a run, Latency = 1, watched while it produces, for its summary
+type tpRunLatency struct {
+	watch  *tpLatencyWatch
+	client *mongo.Client
+	cancel context.CancelFunc
+	done   chan bool
+}
+
+// Start watching the Datastore ahead of the run producing, nil => Latency is off.
+func startLatency() *tpRunLatency {
+
+	if vGeneral.Latency != 1 {
+		return nil
+	}
+
+	Mongoclient, err := connectMongo(vMongodb)
+	if err != nil {
+		grpcLog.Fatal("Mongo Connect Failed: ", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &tpRunLatency{watch: newLatencyWatch(), client: Mongoclient, cancel: cancel, done: make(chan bool)}
+
+	stream, err := r.watch.open(ctx, Mongoclient.Database(vMongodb.Datastore))
+	if err != nil {
+		grpcLog.Fatal("Change Stream Watch Failed: ", err)
+	}
+
+	go func() {
+		r.watch.follow(ctx, stream)
+		close(r.done)
+	}()
+
+	grpcLog.Infoln("* Watching latency            :", vMongodb.Datastore, latencyCollections())
+	return r
+}
+
+// Once the run is done, wait for the documents still on their way, until none arrived for latencySettle, then stop
+// watching.
+func (r *tpRunLatency) stop() {
+
+	if r == nil {
+		return
+	}
+
+	waited := time.Now()
+	for {
+		last := r.watch.latency.arrived()
+		if last.Before(waited) {
+			last = waited
+		}
+		if time.Since(last) >= latencySettle {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+
+	r.cancel()
+	<-r.done
+	r.client.Disconnect(context.TODO())
+	r.watch.push()
+}
+
+// The percentiles per collection, for the run summary.
+func (r *tpRunLatency) report() {
+
+	if r == nil {
+		return
+	}
+
+	grpcLog.Infoln("")
+	grpcLog.Infoln("End-to-end latency            :")
+	printLatency(r.watch.latency)
+}
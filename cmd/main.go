@@ -40,6 +40,33 @@
 *					: 17 June
 *					: Renaming the main repo => *-pb as the Protobuf version & a second version/repo *-json thats json based
 *
+*					: 18 Oct 2026
+*					: Mongo inserts moved into mongo.go, added document Modelling (separate/embedded/upsert) to *_mongo.json
+*					: Run manifest (Manifest = 1) and the verify command, see verify.go
+*					: produceTimestamp on baskets and payments, and the latency command, see latency.go, Latency = 1 reports it in the run summary
+*					: Unified configuration loading (YAML/TOML/JSON, env and command line overrides, validation), see config.go
+*					: Subcommands and flags, see cli.go, the Kafka producer moved into kafka.go, the sinks into sinks.go, replay.go
+*					: Weighted/zipf/pareto store, clerk and product selection, see distribution.go
+*					: Clerks bound to their store and shift rosters, see roster.go
+*					: Terminals per store, basket size and payment method by terminal type, see terminals.go
+*					: Traffic model, opening hours, hourly curve, day/month/date rules, see traffic.go
+*					: backfill, a past date range on a simulated clock, see backfill.go
+*					: Store timezones and TimestampFormat, replacing TimeOffset, see timezones.go
+*					: Loyalty customers on baskets, points earned/redeemed on payments, see customers.go
+*					: Promotions, line and basket discounts, see promotions.go
+*					: Tax categories, per line tax, Tax_inclusive pricing, see tax.go
+*					: Store currencies, FX for foreign cards, cash rounding, see currency.go
+*					: Inventory, stock levels, deliveries and stock-outs, movements to their own topic/collection, see inventory.go
+*					: seed generate, generated seed files of any size for scale testing, see generate.go
+*					: Stores, clerks and products from Mongo, Postgres or CSV, Seed_refresh, see seedsource.go
+*					: Price changes, inflation, scheduled changes, markdowns and price wars, to their own topic/collection, see pricing.go
+*					: Online orders, delivery address, shipping fee and their lifecycle events, see orders.go
+*					: Seed file validation at load, duplicate ids, dangling references, missing fields, prices, and seed validate, see seed.go
+*					: Fraud and anomaly injection, card testing, large baskets, refund abuse, sweethearting, duplicate finTransactionIDs, with ground-truth labels, see anomalies.go
+*					: Data-quality faults, missing/null/wrong type fields, negative quantities, bad totals and timestamps, undeserialisable Kafka payloads, see faults.go
+*					: Scenario files, sequential or overlapping phases each with their own settings and sinks, Late_payments, see scenario.go
+*					: Store profiles, basket size, quantity, price tier and traffic weight per store or store group, see profiles.go
+*
 *
 *
 *	Git				: https://github.com/georgelza/MongoCreator-GoProducer
@@ -62,20 +89,15 @@ import (
 	"math"
 	"math/rand"
 	"os"
-	"runtime"
-	"strconv"
 	"time"
 
 	"github.com/brianvoe/gofakeit"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
-	"github.com/confluentinc/confluent-kafka-go/schemaregistry"
-	"github.com/confluentinc/confluent-kafka-go/schemaregistry/serde/protobuf"
 
 	"github.com/google/uuid"
 
 	"github.com/TylerBrock/colorjson"
-	"github.com/tkanos/gonfig"
 	glog "google.golang.org/grpc/grpclog"
 
 	// My Types/Structs/functions
@@ -85,14 +107,24 @@ import (
 	// MongoDB
 	//
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 var (
 	grpcLog  glog.LoggerV2
 	varSeed  types.TPSeed
+	varPick  tpPickers
+	varTerm  *tpTerminals
+	varTraff *tpTraffic
+	varCust  *tpCustomers
+	varPromo *tpPromotions
+	varTax   *tpTax
+	varCurr  *tpCurrencies
+	varStock *tpInventory
+	varPrice *tpPricing
+	varOrder *tpOrders
+	varFraud *tpAnomalies
+	varFault *tpFaults
+	varProf  tpProfiles
 	vGeneral types.Tp_general
 	pathSep  = string(os.PathSeparator)
 	runId    string
@@ -105,6 +137,10 @@ func init() {
 	// Keeping it very simple
 	grpcLog = glog.NewLoggerV2(os.Stdout, os.Stdout, os.Stdout)
 
+}
+
+func banner() {
+
 	grpcLog.Infoln("###############################################################")
 	grpcLog.Infoln("#")
 	grpcLog.Infoln("#   Project   : GoProducer 2.0 - Protobuf based")
@@ -121,173 +157,21 @@ func init() {
 
 }
 
-func loadConfig(params ...string) types.Tp_general {
-
-	var err error
-
-	vGeneral := types.Tp_general{}
-	env := "dev"
-	if len(params) > 0 { // Input environment was specified, so lets use it
-		env = params[0]
-		grpcLog.Info("*")
-		grpcLog.Info("* Called with Argument => ", env)
-		grpcLog.Info("*")
-
-	}
-
-	vGeneral.CurrentPath, err = os.Getwd()
-	if err != nil {
-		grpcLog.Fatalln("Problem retrieving current path: %s", err)
-
-	}
-
-	vGeneral.OSName = runtime.GOOS
-
-	// General config file
-	fileName := fmt.Sprintf("%s%s%s_app.json", vGeneral.CurrentPath, pathSep, env)
-	err = gonfig.GetConf(fileName, &vGeneral)
-	if err != nil {
-		grpcLog.Fatalln("Error Reading Config File: ", err)
-
-	} else {
-
-		vHostname, err := os.Hostname()
-		if err != nil {
-			grpcLog.Fatalln("Can't retrieve hostname %s", err)
-
-		}
-		vGeneral.Hostname = vHostname
-		vGeneral.SeedFile = fmt.Sprintf("%s%s%s", vGeneral.CurrentPath, pathSep, vGeneral.SeedFile)
-
-	}
-
-	if vGeneral.Json_to_file == 1 {
-		vGeneral.Output_path = fmt.Sprintf("%s%s%s", vGeneral.CurrentPath, pathSep, vGeneral.Output_path)
-	}
-
-	if vGeneral.EchoConfig == 1 {
-		grpcLog.Infoln("*")
-		grpcLog.Infoln("* Config:")
-		grpcLog.Infoln("* Current path:", vGeneral.CurrentPath)
-		grpcLog.Infoln("* Config File :", fileName)
-		grpcLog.Infoln("*")
-
-		printConfig(vGeneral)
-	}
-
-	return vGeneral
-}
-
-// Load Kafka specific configuration Parameters, this is so that we can gitignore this dev_kafka.json file/seperate
-// from the dev_app.json file
-func loadKafka(params ...string) types.TKafka {
-
-	vKafka := types.TKafka{}
-	env := "dev"
-	if len(params) > 0 {
-		env = params[0]
-	}
-
-	path, err := os.Getwd()
-	if err != nil {
-		grpcLog.Error(fmt.Sprintf("Problem retrieving current path: %s", err))
-		os.Exit(1)
-
-	}
-
-	//	fileName := fmt.Sprintf("%s/%s_app.json", path, env)
-	fileName := fmt.Sprintf("%s/%s_kafka.json", path, env)
-	err = gonfig.GetConf(fileName, &vKafka)
-	if err != nil {
-		grpcLog.Error(fmt.Sprintf("Error Reading Kafka File: %s", err))
-		os.Exit(1)
-
-	}
-
-	vGeneral.KafkaConfigFile = fileName
-
-	if vGeneral.Debuglevel > 0 {
-
-		grpcLog.Info("*")
-		grpcLog.Info("* Kafka Config :")
-		grpcLog.Info(fmt.Sprintf("* Current path : %s", path))
-		grpcLog.Info(fmt.Sprintf("* Kafka File   : %s", fileName))
-		grpcLog.Info("*")
-
-	}
-
-	vKafka.Sasl_password = os.Getenv("Sasl_password")
-	vKafka.Sasl_username = os.Getenv("Sasl_username")
-
-	if vGeneral.EchoConfig == 1 {
-		printKafkaConfig(vKafka)
-	}
-
-	return vKafka
-}
-
-func loadMongoProps(params ...string) types.TMongodb {
-
-	vMongodb := types.TMongodb{}
-	env := "dev"
-	if len(params) > 0 {
-		env = params[0]
-	}
-
-	path, err := os.Getwd()
-	if err != nil {
-		grpcLog.Error(fmt.Sprintf("Problem retrieving current path: %s", err))
-		os.Exit(1)
-
-	}
+func loadSeed(fileName string) types.TPSeed {
 
-	//	fileName := fmt.Sprintf("%s/%s_app.json", path, env)
-	fileName := fmt.Sprintf("%s/%s_mongo.json", path, env)
-	err = gonfig.GetConf(fileName, &vMongodb)
+	// The SeedFile, with the stores, clerks and products of the Seed_source, see seedsource.go
+	vSeed, err := readSeed(fileName)
 	if err != nil {
-		grpcLog.Error(fmt.Sprintf("Error Reading Mongo File: %s", err))
-		os.Exit(1)
-
-	}
-
-	vGeneral.MongoConfigFile = fileName
-
-	if vGeneral.Debuglevel > 0 {
-
-		grpcLog.Info("*")
-		grpcLog.Info("* Mongo Config :")
-		grpcLog.Info(fmt.Sprintf("* Current path : %s", path))
-		grpcLog.Info(fmt.Sprintf("* Mongo File   : %s", fileName))
-		grpcLog.Info("*")
+		grpcLog.Fatalln("Error Reading Seed: ", err)
 
 	}
 
-	vMongodb.Username = os.Getenv("mongo_username")
-	vMongodb.Password = os.Getenv("mongo_password")
-
-	if vMongodb.Username != "" {
-
-		vMongodb.Uri = fmt.Sprintf("%s://%s:%s@%s&w=majority", vMongodb.Root, vMongodb.Username, vMongodb.Password, vMongodb.Url)
-
-	} else {
-
-		vMongodb.Uri = fmt.Sprintf("%s://%s&w=majority", vMongodb.Root, vMongodb.Url)
+	// Fail fast on duplicate ids, missing fields etc., before anything is picked from the seed, see seed.go
+	for _, warning := range lintSeed(vSeed) {
+		grpcLog.Warningln("* Seed:", warning)
 	}
-
-	if vGeneral.EchoConfig == 1 {
-		printMongoConfig(vMongodb)
-	}
-
-	return vMongodb
-}
-
-func loadSeed(fileName string) types.TPSeed {
-
-	var vSeed types.TPSeed
-
-	err := gonfig.GetConf(fileName, &vSeed)
-	if err != nil {
-		grpcLog.Fatalln("Error Reading Seed File: ", err)
+	if problems := validateSeed(vSeed); len(problems) > 0 {
+		fatalProblems(fmt.Sprintf("Seed File %s problems:", fileName), problems)
 
 	}
 
@@ -306,6 +190,7 @@ func loadSeed(fileName string) types.TPSeed {
 		grpcLog.Infoln("* Seed :")
 		grpcLog.Infoln("* Current path:", vGeneral.CurrentPath)
 		grpcLog.Infoln("* Seed File   :", vGeneral.SeedFile)
+		grpcLog.Infoln("* Seed Source :", vGeneral.Seed_source)
 		grpcLog.Infoln("*")
 
 	}
@@ -327,11 +212,16 @@ func printConfig(vGeneral types.Tp_general) {
 	grpcLog.Info("* Echo Seed is\t\t", vGeneral.EchoSeed)
 	grpcLog.Info("* Seed File is\t\t", vGeneral.SeedFile)
 	grpcLog.Info("* Json to File is\t\t", vGeneral.Json_to_file)
-	if vGeneral.Json_to_file == 1 {
+	grpcLog.Info("* Manifest is\t\t\t", vGeneral.Manifest)
+	grpcLog.Info("* Latency is\t\t\t", vGeneral.Latency)
+	if vGeneral.Json_to_file == 1 || vGeneral.Manifest == 1 {
 		grpcLog.Infoln("* Output path\t\t\t", vGeneral.Output_path)
 	}
 	grpcLog.Info("* Kafka Enabled is\t\t", vGeneral.KafkaEnabled)
 	grpcLog.Info("* Mongo Enabled is\t\t", vGeneral.MongoAtlasEnabled)
+	if vGeneral.Prom_pushgateway != "" {
+		grpcLog.Info("* Prometheus Push Gateway is\t", vGeneral.Prom_pushgateway)
+	}
 
 	grpcLog.Info("*")
 	grpcLog.Info("*******************************")
@@ -351,6 +241,18 @@ func printKafkaConfig(vKafka types.TKafka) {
 	grpcLog.Info("* Kafka schema Registry is\t", vKafka.SchemaRegistryURL)
 	grpcLog.Info("* Kafka Basket Topic is\t", vKafka.BasketTopicname)
 	grpcLog.Info("* Kafka Payment Topic is\t", vKafka.PaymentTopicname)
+	if vKafka.InventoryTopicname != "" {
+		grpcLog.Info("* Kafka Inventory Topic is\t", vKafka.InventoryTopicname)
+	}
+	if vKafka.PriceTopicname != "" {
+		grpcLog.Info("* Kafka Price Topic is\t\t", vKafka.PriceTopicname)
+	}
+	if vKafka.OrderTopicname != "" {
+		grpcLog.Info("* Kafka Order Topic is\t\t", vKafka.OrderTopicname)
+	}
+	if vKafka.LabelTopicname != "" {
+		grpcLog.Info("* Kafka Label Topic is\t\t", vKafka.LabelTopicname)
+	}
 	grpcLog.Info("* Kafka # Parts is\t\t", vKafka.Numpartitions)
 	grpcLog.Info("* Kafka Rep Factor is\t\t", vKafka.Replicationfactor)
 	grpcLog.Info("* Kafka Retension is\t\t", vKafka.Retension)
@@ -381,7 +283,24 @@ func printMongoConfig(vMongodb types.TMongodb) {
 	grpcLog.Info("* Mongo Username is\t\t", vMongodb.Username)
 	grpcLog.Info("* Mongo Basket Collection is\t", vMongodb.Basketcollection)
 	grpcLog.Info("* Mongo Payment Collection is\t", vMongodb.Paymentcollection)
+	if vMongodb.Inventorycollection != "" {
+		grpcLog.Info("* Mongo Inventory Collection is\t", vMongodb.Inventorycollection)
+	}
+	if vMongodb.Pricecollection != "" {
+		grpcLog.Info("* Mongo Price Collection is\t", vMongodb.Pricecollection)
+	}
+	if vMongodb.Ordercollection != "" {
+		grpcLog.Info("* Mongo Order Collection is\t", vMongodb.Ordercollection)
+	}
+	if vMongodb.Labelcollection != "" {
+		grpcLog.Info("* Mongo Label Collection is\t", vMongodb.Labelcollection)
+	}
+	grpcLog.Info("* Mongo Modelling is\t\t", vMongodb.Modelling)
+	if vMongodb.Modelling == modelEmbedded {
+		grpcLog.Info("* Mongo Sales Collection is\t", vMongodb.Salescollection)
+	}
 	grpcLog.Info("* Mongo Batch szie is\t\t", vMongodb.Batch_size)
+	grpcLog.Info("* Mongo Verify Wait is\t\t", vMongodb.Verify_wait)
 
 	grpcLog.Info("*")
 	grpcLog.Info("*******************************")
@@ -442,59 +361,39 @@ func CreateTopic(props types.TKafka) {
 
 	}
 
-	// FIX THIS - Nasty version/hack for now.
-	// Basket topic
-	results, err := adminClient.CreateTopics(ctx,
-		[]kafka.TopicSpecification{{
-			Topic:             props.BasketTopicname,
-			NumPartitions:     props.Numpartitions,
-			ReplicationFactor: props.Replicationfactor}},
-		kafka.SetAdminOperationTimeout(maxDuration))
-
-	if err != nil {
-		grpcLog.Error(fmt.Sprintf("Problem during the topic creation: %v", err))
-		os.Exit(1)
-	}
-
-	// Check for specific topic errors
-	for _, result := range results {
-		if result.Error.Code() != kafka.ErrNoError &&
-			result.Error.Code() != kafka.ErrTopicAlreadyExists {
-			grpcLog.Error(fmt.Sprintf("Topic Creation Failed for %s: %v", result.Topic, result.Error.String()))
-			os.Exit(1)
-
-		} else {
-			if vGeneral.Debuglevel > 0 {
-				grpcLog.Info(fmt.Sprintf("* Topic Creation Succeeded for %s", result.Topic))
-
-			}
+	// Basket, Payment and, if we post the inventory movements, price changes, order events and anomaly labels, their topics
+	topics := []string{props.BasketTopicname, props.PaymentTopicname}
+	for _, topic := range []string{props.InventoryTopicname, props.PriceTopicname, props.OrderTopicname, props.LabelTopicname} {
+		if topic != "" {
+			topics = append(topics, topic)
 		}
 	}
 
-	// Payment topic
-	results, err = adminClient.CreateTopics(ctx,
-		[]kafka.TopicSpecification{{
-			Topic:             props.PaymentTopicname,
-			NumPartitions:     props.Numpartitions,
-			ReplicationFactor: props.Replicationfactor}},
-		kafka.SetAdminOperationTimeout(maxDuration))
+	for _, topic := range topics {
+		results, err := adminClient.CreateTopics(ctx,
+			[]kafka.TopicSpecification{{
+				Topic:             topic,
+				NumPartitions:     props.Numpartitions,
+				ReplicationFactor: props.Replicationfactor}},
+			kafka.SetAdminOperationTimeout(maxDuration))
 
-	if err != nil {
-		grpcLog.Error(fmt.Sprintf("Problem during the topic creation: %v", err))
-		os.Exit(1)
-	}
-
-	// Check for specific topic errors
-	for _, result := range results {
-		if result.Error.Code() != kafka.ErrNoError &&
-			result.Error.Code() != kafka.ErrTopicAlreadyExists {
-			grpcLog.Error(fmt.Sprintf("Topic Creation Failed for %s: %v", result.Topic, result.Error.String()))
+		if err != nil {
+			grpcLog.Error(fmt.Sprintf("Problem during the topic creation: %v", err))
 			os.Exit(1)
+		}
+
+		// Check for specific topic errors
+		for _, result := range results {
+			if result.Error.Code() != kafka.ErrNoError &&
+				result.Error.Code() != kafka.ErrTopicAlreadyExists {
+				grpcLog.Error(fmt.Sprintf("Topic Creation Failed for %s: %v", result.Topic, result.Error.String()))
+				os.Exit(1)
 
-		} else {
-			if vGeneral.Debuglevel > 0 {
-				grpcLog.Info(fmt.Sprintf("* Topic Creation Succeeded for %s", result.Topic))
+			} else {
+				if vGeneral.Debuglevel > 0 {
+					grpcLog.Info(fmt.Sprintf("* Topic Creation Succeeded for %s", result.Topic))
 
+				}
 			}
 		}
 	}
@@ -565,7 +464,8 @@ func toFixed(num float64, precision int) float64 {
 	return float64(round(num*output)) / output
 }
 
-func constructFakeBasket() (pb_Basket types.Pb_Basket, eventTimestamp time.Time, storeName string, err error) {
+// Build a basket for a sale at time at, as per the traffic at that time.
+func constructFakeBasket(traffic tpTrafficAt, at time.Time) (pb_Basket types.Pb_Basket, eventTimestamp time.Time, storeName string, err error) {
 
 	// Fake Data etc, not used much here though
 	// https://github.com/brianvoe/gofakeit
@@ -573,70 +473,77 @@ func constructFakeBasket() (pb_Basket types.Pb_Basket, eventTimestamp time.Time,
 
 	gofakeit.Seed(0)
 
-	var store types.Idstruct
-	var clerk types.Idstruct
-	if vGeneral.Store == 0 {
-		// Determine how many Stores we have in seed file,
-		// and build the 2 structures from that viewpoint
-		storeCount := len(varSeed.Stores) - 1
-		nStoreId := gofakeit.Number(0, storeCount)
-		store.Id = varSeed.Stores[nStoreId].Id
-		store.Name = varSeed.Stores[nStoreId].Name
-
-	} else {
-		// We specified a specific store
-		store.Id = varSeed.Stores[vGeneral.Store].Id
-		store.Name = varSeed.Stores[vGeneral.Store].Name
+	// The prices as at the sale, see pricing.go
+	varPrice.update(&varSeed, at)
+
+	// Pick a store, as per the Distribution (or the one specified), and one of its clerks on shift
+	nStoreId, nClerkId := pickStoreClerk(varSeed, at)
 
+	// time that everything happened, in the store's timezone, the 1st as a Unix Epoc time representation,
+	// the 2nd as per the TimestampFormat.
+	eventTimestamp = storeTime(varSeed.Stores[nStoreId], at)
+	eventTime := formatTimestamp(eventTimestamp)
+
+	store := types.Idstruct{
+		Id:   varSeed.Stores[nStoreId].Id,
+		Name: varSeed.Stores[nStoreId].Name,
+	}
+	clerk := types.Idstruct{
+		Id:   varSeed.Clerks[nClerkId].Id,
+		Name: varSeed.Clerks[nClerkId].Name,
 	}
 
-	// Determine how many Clerks we have in seed file,
-	clerkCount := len(varSeed.Clerks) - 1
-	nClerkId := gofakeit.Number(0, clerkCount)
-	clerk.Id = varSeed.Clerks[nClerkId].Id
-	clerk.Name = varSeed.Clerks[nClerkId].Name
+	// The terminal decides how big the basket is, and later how it's paid
+	terminal, profile := varTerm.pick(nStoreId)
+
+	// A loyalty customer, or an anonymous sale
+	customer := varCust.pick(varSeed.Stores[nStoreId])
 
 	// Uniqiue reference to the basket/sale
 	txnId := uuid.New().String()
 
-	// time that everything happened, the 1st as a Unix Epoc time representation,
-	// the 2nd in nice human readable milli second representation.
-	eventTimestamp = time.Now()
-	eventTime := eventTimestamp.Format("2006-01-02T15:04:05.000") + vGeneral.TimeOffset
+	// now pick from array a random products to add to basket, as per the store's profile, or else the terminal type's basket size.
+	nBasketItems := traffic.basketItems(varProf.items(varSeed.Stores[nStoreId], profile.MinItems, profile.MaxItems))
 
-	// How many potential products do we have
-	productCount := len(varSeed.Products) - 1
-	// now pick from array a random products to add to basket, by using 1 as a start point we ensure we always have at least 1 item.
-	nBasketItems := gofakeit.Number(1, vGeneral.Max_items_basket)
+	// All the amounts are in the store's currency
+	currency := storeCurrency(varSeed.Stores[nStoreId])
+
+	// Deliveries due since the store's last sale
+	varStock.deliver(varSeed, varSeed.Stores[nStoreId], eventTimestamp)
 
 	var BasketItems []*types.BasketItem
-	nett_amount := 0.0
 
 	for count := 0; count < nBasketItems; count++ {
 
-		productId := gofakeit.Number(0, productCount)
+		productId := traffic.products.pick()
+
+		quantity := varProf.quantity(varSeed.Stores[nStoreId])
 
-		quantity := gofakeit.Number(1, vGeneral.Max_quantity)
-		price := varSeed.Products[productId].Price
+		// Off the shelf, out of stock items are dropped or substituted
+		productId, quantity = varStock.take(varSeed, varSeed.Stores[nStoreId], productId, quantity, txnId, eventTimestamp)
+		if quantity == 0 {
+			continue
+		}
 
 		BasketItem := &types.BasketItem{
-			Id:       varSeed.Products[productId].Id,
-			Name:     varSeed.Products[productId].Name,
-			Brand:    varSeed.Products[productId].Brand,
-			Category: varSeed.Products[productId].Category,
-			Price:    varSeed.Products[productId].Price,
-			Quantity: int32(quantity),
+			Id:          varSeed.Products[productId].Id,
+			Name:        varSeed.Products[productId].Name,
+			Brand:       varSeed.Products[productId].Brand,
+			Category:    varSeed.Products[productId].Category,
+			Price:       varCurr.price(varProf.price(varSeed.Stores[nStoreId], varSeed.Products[productId].Price), currency),
+			Quantity:    int32(quantity),
+			TaxCategory: varTax.category(varSeed.Products[productId]),
 		}
 		BasketItems = append(BasketItems, BasketItem)
 
-		nett_amount = nett_amount + price*float64(quantity)
-
 	}
 
-	nett_amount = toFixed(nett_amount, 2)
-	vat_amount := toFixed(nett_amount*vGeneral.Vatrate, 2) // sales tax
+	// The promotions on at the time in the store
+	discount := varPromo.apply(BasketItems, varSeed.Stores[nStoreId], eventTimestamp)
+
+	// Tax per line, nett is after the discount, excluding the tax
+	nett_amount, vat_amount := varTax.apply(BasketItems)
 	total_amount := toFixed(nett_amount+vat_amount, 2)
-	terminalPoint := gofakeit.Number(0, 20)
 
 	pb_Basket = types.Pb_Basket{
 		InvoiceNumber: txnId,
@@ -644,21 +551,38 @@ func constructFakeBasket() (pb_Basket types.Pb_Basket, eventTimestamp time.Time,
 		SaleTimestamp: fmt.Sprint(eventTimestamp.UnixMilli()),
 		Store:         &store,
 		Clerk:         &clerk,
-		TerminalPoint: strconv.Itoa(terminalPoint),
+		TerminalPoint: terminal.Id,
+		TerminalType:  terminal.Type,
+		Customer:      customer,
 		BasketItems:   BasketItems,
+		Discount:      discount,
+		Currency:      currency,
 		Nett:          nett_amount,
 		Vat:           vat_amount,
 		Total:         total_amount,
 	}
 
+	// Online orders are delivered, see orders.go
+	varOrder.ship(&pb_Basket, varSeed.Stores[nStoreId], customer)
+
 	return pb_Basket, eventTimestamp, store.Name, nil
 }
 
-func constructPayments(txnId string, eventTimestamp time.Time, total_amount float64) (pb_Payment types.Pb_Payment, err error) {
+func constructPayments(txnId string, eventTimestamp time.Time, total_amount float64, terminalType string, customer *types.Customer, currency string) (pb_Payment types.Pb_Payment, err error) {
 
 	// We're saying payment can be now up to 5min and 59 seconds later
-	payTimestamp := eventTimestamp.Local().Add(time.Minute*time.Duration(gofakeit.Number(0, 5)) + time.Second*time.Duration(gofakeit.Number(0, 59)))
-	payTime := payTimestamp.Format("2006-01-02T15:04:05.000") + vGeneral.TimeOffset
+	// eventTimestamp is in the store's timezone, so is the payment
+	payTimestamp := eventTimestamp.Add(time.Minute*time.Duration(gofakeit.Number(0, 5)) + time.Second*time.Duration(gofakeit.Number(0, 59)))
+
+	// Late_payments are made Late_delay minutes (on average) after the sale, late for a join on a short window
+	if vGeneral.Late_payments > 0 && rand.Float64() < vGeneral.Late_payments {
+		delay := vGeneral.Late_delay
+		if delay <= 0 {
+			delay = 60
+		}
+		payTimestamp = eventTimestamp.Add(time.Duration(float64(delay) * (0.5 + rand.Float64()) * float64(time.Minute)))
+	}
+	payTime := formatTimestamp(payTimestamp)
 
 	pb_Payment = types.Pb_Payment{
 		InvoiceNumber:    txnId,
@@ -666,222 +590,143 @@ func constructPayments(txnId string, eventTimestamp time.Time, total_amount floa
 		PayTimestamp:     fmt.Sprint(payTimestamp.UnixMilli()),
 		Paid:             total_amount,
 		FinTransactionID: uuid.New().String(),
+		PaymentMethod:    varTerm.paymentMethod(terminalType),
+	}
+
+	// Loyalty points, redeemed points pay part of the total
+	if customer != nil {
+		pb_Payment.CustomerId = customer.Id
+		pb_Payment.PointsEarned, pb_Payment.PointsRedeemed, pb_Payment.PointsAmount, pb_Payment.PointsBalance = varCust.settle(customer.Id, total_amount)
+		pb_Payment.Paid = toFixed(total_amount-pb_Payment.PointsAmount, 2)
+	}
+
+	// Cash is rounded to the currency's smallest coin, a foreign card is charged in its own currency
+	pb_Payment.Currency = currency
+	switch pb_Payment.PaymentMethod {
+	case "cash":
+		due := pb_Payment.Paid
+		pb_Payment.Paid = varCurr.cashRound(due, currency)
+		pb_Payment.Rounding = toFixed(pb_Payment.Paid-due, 2)
+
+	case "card":
+		pb_Payment.CardNumber = cardNumber()
+		if card := varCurr.cardCurrency(currency); card != "" {
+			pb_Payment.CardCurrency = card
+			pb_Payment.FxRate = toFixed(varCurr.rate(currency)/varCurr.rate(card), 6)
+			pb_Payment.CardAmount = varCurr.convert(pb_Payment.Paid, currency, card)
+		}
 	}
 
 	return pb_Payment, nil
 }
 
-// Big worker... This is where all the magic is called from, ha ha.
-func runLoader(arg string) {
+// The seed, and everything we derive from it, shared by produce, backfill and the scenario phases.
+func loadRun() {
 
-	var err error
-	var f_basket *os.File
-	var f_pmnt *os.File
-	var basketcol *mongo.Collection
-	var paymentcol *mongo.Collection
-	var client schemaregistry.Client
-	var serializer *protobuf.Serializer
-	var p *kafka.Producer
-
-	// Initialize the vGeneral struct variable - This holds our configuration settings.
-	vGeneral = loadConfig(arg)
+	// each run is identified by a runId, used to name the json_save files and the manifest that verify reconciles against.
+	runId = uuid.New().String()
+	grpcLog.Infoln("* Run Id                      :", runId)
 
 	// Lets get Seed Data from the specified seed file
 	varSeed = loadSeed(vGeneral.SeedFile)
 
-	// Initiale the vKafka struct variable - This holds our Confluent Kafka configuration settings.
-	// if Kafka is enabled then create the confluent kafka connection session/objects
-	if vGeneral.KafkaEnabled == 1 {
-
-		vKafka = loadKafka(arg)
-
-		// Lets make sure the topic/s exist
-		CreateTopic(vKafka)
+	if problems := validateSeedConfig(vGeneral, varSeed); len(problems) > 0 {
+		fatalProblems("Configuration and Seed do not match:", problems)
+
+	}
+	varProf = newProfiles(varSeed)
+	varPick = newPickers(varSeed)
+	varTerm = newTerminals(varSeed)
+	varTraff = newTraffic(varSeed)
+	varCust = newCustomers(varSeed)
+	varPromo = newPromotions(varSeed)
+	varTax = newTax(varSeed)
+	varCurr = newCurrencies(varSeed)
+	varStock = newInventory(varSeed)
+	varPrice = newPricing(varSeed)
+	varOrder = newOrders(varSeed)
+	varFraud = newAnomalies(varSeed)
+	varFault = newFaults(vGeneral)
+}
 
-		// --
-		// Create Producer instance
-		// https://docs.confluent.io/current/clients/confluent-kafka-go/index.html#NewProducer
+// One sale, at now, into the sinks, its basket, payment, and the inventory movements, price changes, order events
+// and anomaly labels that go with it, pause => the Sleep between the basket and payment. false => everything was out
+// of stock, the customer walked out.
+func sell(sinks *tpSinks, traffic tpTrafficAt, now time.Time, pause bool) bool {
 
-		if vGeneral.Debuglevel > 0 {
-			grpcLog.Info("**** Configure Client Kafka Connection ****")
-			grpcLog.Info("*")
-			grpcLog.Info(fmt.Sprintf("* Kafka bootstrap Server is %s", vKafka.Bootstrapservers))
-			if vKafka.SchemaRegistryURL != "" {
-				grpcLog.Info(fmt.Sprintf("* Schema Registry URL is    %s", vKafka.SchemaRegistryURL))
-			}
-		}
+	// The online order steps that are due by now
+	sinks.postOrderEvents(varOrder.due(now))
 
-		cm := kafka.ConfigMap{
-			"bootstrap.servers":       vKafka.Bootstrapservers,
-			"broker.version.fallback": "0.10.0.0",
-			"api.version.fallback.ms": 0,
-			"client.id":               vGeneral.Hostname,
-		}
+	// Build an sales basket
+	pb_Basket, eventTimestamp, _, err := constructFakeBasket(traffic, now)
+	if err != nil {
+		grpcLog.Fatalln("Fatal constructFakeBasket: ", err)
 
-		if vGeneral.Debuglevel > 0 {
-			grpcLog.Info("* Basic Client ConfigMap compiled")
+	}
 
-		}
+	// Price changes go ahead of the baskets sold at the new prices
+	sinks.postPriceChanges(varPrice.drain())
 
-		if vKafka.Sasl_mechanisms != "" {
-			cm["sasl.mechanisms"] = vKafka.Sasl_mechanisms
-			cm["security.protocol"] = vKafka.Security_protocol
-			cm["sasl.username"] = vKafka.Sasl_username
-			cm["sasl.password"] = vKafka.Sasl_password
-			if vGeneral.Debuglevel > 0 {
-				grpcLog.Info("* Security Authentifaction configured in ConfigMap")
+	// Everything in the basket was out of stock, the customer walked out
+	if len(pb_Basket.BasketItems) == 0 {
+		sinks.postMovements(varStock.drain())
+		return false
+	}
 
-			}
-			fmt.Println("Mechanism", vKafka.Sasl_mechanisms)
-			fmt.Println("Username", vKafka.Sasl_username)
-			fmt.Println("Password", vKafka.Sasl_password)
-			fmt.Println("Broker", vKafka.Bootstrapservers)
-		}
+	// Lets sleep a bit before creating SalesPayment, when not running at a fixed rate
+	if vGeneral.Sleep > 0 && vGeneral.Rate == 0 && pause {
+		n := rand.Intn(vGeneral.Sleep)
+		time.Sleep(time.Duration(n) * time.Millisecond)
+	}
 
-		// Variable p holds the new Producer instance.
-		p, err = kafka.NewProducer(&cm)
-		if err != nil {
-			grpcLog.Error(fmt.Sprintf("Failed to create producer: %s", err))
+	// Fraud and anomalies, the basket as is, changed, or a card testing burst, see anomalies.go
+	sales, labels := varFraud.inject(&pb_Basket, eventTimestamp)
 
-		}
-		defer p.Close()
+	for i := range sales {
+		sale := sales[i].basket
 
-		// Check for errors in creating the Producer
+		// Build an payment record for created sales basket
+		pb_Payment, err := constructPayments(sale.InvoiceNumber, sales[i].at, sale.Total, sale.TerminalType, sale.Customer, sale.Currency)
 		if err != nil {
-			grpcLog.Error(fmt.Sprintf("😢Oh noes, there's an error creating the Producer! %s", err))
-
-			if ke, ok := err.(kafka.Error); ok {
-				switch ec := ke.Code(); ec {
-				case kafka.ErrInvalidArg:
-					grpcLog.Error(fmt.Sprintf("😢 Can't create the producer because you've configured it wrong (code: %d)!\n\t%v\n\nTo see the configuration options, refer to https://github.com/edenhill/librdkafka/blob/master/CONFIGURATION.md", ec, err))
-				default:
-					grpcLog.Error(fmt.Sprintf("😢 Can't create the producer (Kafka error code %d)\n\tError: %v\n", ec, err))
-				}
-
-			} else {
-				// It's not a kafka.Error
-				grpcLog.Error(fmt.Sprintf("😢 Oh noes, there's a generic error creating the Producer! %v", err.Error()))
-			}
-			// call it when you know it's broken
-			os.Exit(1)
+			grpcLog.Fatalln("Fatal constructPayments: ", err)
 
 		}
-
-		// Create a new Schema Registry client
-		client, err = schemaregistry.NewClient(schemaregistry.NewConfig(vKafka.SchemaRegistryURL))
-		if err != nil {
-			grpcLog.Error(fmt.Sprintf("Failed to create Schema Registry client: %s", err))
-
+		if label := varFraud.pay(sale, &pb_Payment); label != nil {
+			labels = append(labels, label)
 		}
 
-		serdeConfig := protobuf.NewSerializerConfig()
-		//serdeConfig.AutoRegisterSchemas = false
-		//serdeConfig.UseLatestVersion = true
-		//serdeConfig.EnableValidation = true
+		sinks.post(sale, &pb_Payment)
+		sinks.postMovements(varStock.drain())
 
-		// Create a Protobuf serializer
-		serializer, err = protobuf.NewSerializer(client, 2, serdeConfig)
-		if err != nil {
-			grpcLog.Errorln(fmt.Sprintf("Failed to create Protobuf serializer: %s", err))
-
-		}
-
-		if vGeneral.Debuglevel > 0 {
-			grpcLog.Infoln("* Created Kafka Producer instance :")
-			grpcLog.Infoln("")
-		}
+		// An online order, its lifecycle from here on, placed now
+		varOrder.place(sale, &pb_Payment)
+		sinks.postOrderEvents(varOrder.due(now))
 	}
+	sinks.postLabels(labels)
 
-	if vGeneral.MongoAtlasEnabled == 1 {
-
-		vMongodb = loadMongoProps(arg)
-
-		serverAPI := options.ServerAPI(options.ServerAPIVersion1)
-
-		opts := options.Client().ApplyURI(vMongodb.Uri).SetServerAPIOptions(serverAPI)
-
-		grpcLog.Infoln("* MongoDB URI Constructed: ", vMongodb.Uri)
-
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		grpcLog.Infoln("* MongoDB Context Object Created")
-
-		defer cancel()
-
-		Mongoclient, err := mongo.Connect(ctx, opts)
-		if err != nil {
-			grpcLog.Fatal("Mongo Connect Failed: ", err)
-		}
-		grpcLog.Infoln("* MongoDB Client Connected")
-
-		defer func() {
-			if err = Mongoclient.Disconnect(ctx); err != nil {
-				grpcLog.Fatal("Mongo Disconected: ", err)
-			}
-		}()
-
-		// Ping the primary
-		if err := Mongoclient.Ping(ctx, readpref.Primary()); err != nil {
-			grpcLog.Fatal("There was a error creating the Client object, Ping failed: ", err)
-		}
-		grpcLog.Infoln("* MongoDB Client Pinged")
+	return true
+}
 
-		// Create go routine to defer the closure
-		defer func() {
-			if err = Mongoclient.Disconnect(context.TODO()); err != nil {
-				grpcLog.Fatal("Mongo Disconected: ", err)
-			}
-		}()
+// Big worker... This is where all the magic is called from, ha ha.
+func runLoader(opts *tpOptions) {
 
-		// Define the Mongo Datastore
-		appLabDatabase := Mongoclient.Database(vMongodb.Datastore)
-		// Define the Mongo Collection Object
-		basketcol = appLabDatabase.Collection(vMongodb.Basketcollection)
-		paymentcol = appLabDatabase.Collection(vMongodb.Paymentcollection)
+	// Initialize the vGeneral, vKafka and vMongodb struct variables - These holds our configuration settings.
+	loadSettings(opts, false, false)
+	loadRun()
 
-		if vGeneral.Debuglevel > 0 {
-			grpcLog.Infoln("* MongoDB Datastore and Collections Intialized")
-			grpcLog.Infoln("*")
-		}
+	// The wall clock, or backfill's simulated one
+	clock := newClock(opts)
 
+	// A backfill runs for its date range, unless --count says otherwise
+	if clock.simulated() && opts.count < 0 {
+		vGeneral.Testsize = 0
 	}
 
-	//We've said we want to safe records to file so lets initiale the file handles etc.
-	if vGeneral.Json_to_file == 1 {
-
-		// each time we run, and say we want to store the data created to disk, we create a pair of files for that run.
-		// this runId is used as the file name, prepended to either _basket.json or _pmnt.json
-		runId = uuid.New().String()
+	sinks := openSinks(opts.dryRun)
+	refresh := newSeedRefresh()
 
-		// Open file -> Baskets
-		loc_basket := fmt.Sprintf("%s%s%s_%s.json", vGeneral.Output_path, pathSep, runId, "basket")
-		if vGeneral.Debuglevel > 2 {
-			grpcLog.Infoln("Basket File          :", loc_basket)
-
-		}
-
-		f_basket, err = os.OpenFile(loc_basket, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		if err != nil {
-			grpcLog.Errorln("os.OpenFile error A", err)
-			panic(err)
-
-		}
-		defer f_basket.Close()
-
-		// Open file -> Payment
-		loc_pmnt := fmt.Sprintf("%s%s%s_%s.json", vGeneral.Output_path, pathSep, runId, "pmnt")
-		if vGeneral.Debuglevel > 2 {
-			grpcLog.Infoln("Payment File         :", loc_basket)
-
-		}
-
-		f_pmnt, err = os.OpenFile(loc_pmnt, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		if err != nil {
-			grpcLog.Errorln("os.OpenFile error A", err)
-			panic(err)
-
-		}
-		defer f_pmnt.Close()
-	}
+	// Latency = 1, watch the documents arrive, for the summary, see latency.go
+	latency := startLatency()
 
 	if vGeneral.Debuglevel > 0 {
 		grpcLog.Info("**** LETS GO Processing ****")
@@ -894,25 +739,11 @@ func runLoader(arg string) {
 		vGeneral.Testsize = 10000000000000
 	}
 
-	//
-	// For signalling termination from main to go-routine
-	var termChan = make(chan bool, 1)
-	// For signalling that termination is done from go-routine to main
-	var doneChan = make(chan bool)
-
-	var basketdocs = make([]interface{}, vMongodb.Batch_size)
-	var paymentdocs = make([]interface{}, vMongodb.Batch_size)
-
-	var json_SalesBasket []byte
-	var json_Payment []byte
-
-	// We will use this to remember when we last flushed the kafka queues and mongo collection.
-	var vFlush = 0
-	var msg_mongo_count = 0
-
 	// this is to keep record of the total batch run time
 	var vStart = time.Now()
-	for count := 0; count < vGeneral.Testsize; count++ {
+	count := 0
+	walkouts := 0
+	for ; count < vGeneral.Testsize; count++ {
 
 		reccount := fmt.Sprintf("%v", count+1)
 
@@ -925,319 +756,33 @@ func runLoader(arg string) {
 		// We're going to time every record and push that to prometheus
 		txnStart := time.Now()
 
-		// Build an sales basket
-		pb_Basket, eventTimestamp, storeName, err := constructFakeBasket()
-		if err != nil {
-			grpcLog.Fatalln("Fatal constructFakeBasket: %s", err)
-			os.Exit(1)
-
-		}
-
-		// Lets sleep a bit before creating SalesPayment
-		if vGeneral.Sleep > 0 {
-			n := rand.Intn(vGeneral.Sleep)
-			time.Sleep(time.Duration(n) * time.Millisecond)
-		}
-
-		// Build an payment record for created sales basket
-		pb_Payment, err := constructPayments(pb_Basket.InvoiceNumber, eventTimestamp, pb_Basket.Total)
-		if err != nil {
-			grpcLog.Fatalln("Fatal constructPayments: %s", err)
-			os.Exit(1)
-
-		}
-
-		json_SalesBasket, err = json.Marshal(pb_Basket)
-		if err != nil {
-			grpcLog.Fatalln("json_SalesBasket Marshal: %s", err)
-			os.Exit(1)
-
-		}
-
-		json_Payment, err = json.Marshal(pb_Payment)
-		if err != nil {
-			grpcLog.Fatalln("json_Payment Marshal: %s", err)
-			os.Exit(1)
-
-		}
-
-		// echo to screen
-		if vGeneral.Debuglevel >= 2 {
-			prettyJSON(string(json_SalesBasket))
-			prettyJSON(string(json_Payment))
-		}
-
-		// Post to Confluent Kafka - if enabled
-		if vGeneral.KafkaEnabled == 1 {
-
-			if vGeneral.Debuglevel >= 2 {
-				grpcLog.Info("")
-				grpcLog.Info("Post to Confluent Kafka topics")
-			}
-
-			// Proto serialize SalesBasket
-			valueBytes, err := serializer.Serialize(vKafka.BasketTopicname, &pb_Basket)
-			if err != nil {
-				grpcLog.Fatalf("Basket: Failed to serialize record: %s", err)
-			}
-
-			kafkaMsg := kafka.Message{
-				TopicPartition: kafka.TopicPartition{
-					Topic:     &vKafka.BasketTopicname,
-					Partition: kafka.PartitionAny,
-				},
-				Value: valueBytes,        // This is the payload/body thats being posted
-				Key:   []byte(storeName), // We us this to group the same transactions together in order, IE submitting/Debtor Bank.
-			}
-
-			// This is where we publish message onto the topic... on the Confluent cluster for now,
-			if err := p.Produce(&kafkaMsg, nil); err != nil {
-				grpcLog.Error(fmt.Sprintf("😢 Darn, there's an error producing the message! %s", err.Error()))
-
-			}
-
-			// Proto serialize SalesPayment
-			valueBytes, err = serializer.Serialize(vKafka.PaymentTopicname, &pb_Payment)
-			if err != nil {
-				grpcLog.Fatalf("Payment: Failed to serialize record: %s", err)
-			}
-
-			kafkaMsg = kafka.Message{
-				TopicPartition: kafka.TopicPartition{
-					Topic:     &vKafka.PaymentTopicname,
-					Partition: kafka.PartitionAny,
-				},
-				Value: valueBytes,        // This is the payload/body thats being posted
-				Key:   []byte(storeName), // We us this to group the same transactions together in order, IE submitting/Debtor Bank.
-			}
-
-			// This is where we publish message onto the topic... on the Confluent cluster for now,
-			if err := p.Produce(&kafkaMsg, nil); err != nil {
-				grpcLog.Errorf("😢 Darn, there's an error producing the message! %s", err.Error())
-
-			}
-
-			vFlush++
-
-			// Fush every flush_interval loops
-			if vFlush == vKafka.Flush_interval {
-				t := 10000
-				if r := p.Flush(t); r > 0 {
-					grpcLog.Error(fmt.Sprintf("Failed to flush all messages after %d milliseconds. %d message(s) remain", t, r))
-
-				} else {
-					if vGeneral.Debuglevel >= 1 {
-						grpcLog.Info(fmt.Sprintf("%s/%s, Messages flushed from the queue", count, vFlush))
-
-					}
-					vFlush = 0
-				}
-			}
-
-			// We will decide if we want to keep this bit!!! or simplify it.
-			//
-			// Convenient way to Handle any events (back chatter) that we get
-			go func() {
-				doTerm := false
-				for !doTerm {
-					// The `select` blocks until one of the `case` conditions
-					// are met - therefore we run it in a Go Routine.
-					select {
-					case ev := <-p.Events():
-						// Look at the type of Event we've received
-						switch ev.(type) {
-
-						case *kafka.Message:
-							// It's a delivery report
-							km := ev.(*kafka.Message)
-							if km.TopicPartition.Error != nil {
-								grpcLog.Error(fmt.Sprintf("☠️ Failed to send message to topic '%v'\tErr: %v",
-									string(*km.TopicPartition.Topic),
-									km.TopicPartition.Error))
-
-							} else {
-								if vGeneral.Debuglevel > 2 {
-									grpcLog.Info(fmt.Sprintf("✅ Message delivered to topic '%v'(partition %d at offset %d)",
-										string(*km.TopicPartition.Topic),
-										km.TopicPartition.Partition,
-										km.TopicPartition.Offset))
-
-								}
-							}
-
-						case kafka.Error:
-							// It's an error
-							em := ev.(kafka.Error)
-							grpcLog.Error(fmt.Sprint("☠️ Uh oh, caught an error:\n\t%v", em))
-
-						}
-					case <-termChan:
-						doTerm = true
-
-					}
-				}
-				close(doneChan)
-			}()
-
+		// Seed_refresh, pick up the seed's changes, see seedsource.go
+		if refresh.due() {
+			refreshSeed()
 		}
 
-		// Do we want to insertrecords/documents directly into Mongo Atlas?
-		if vGeneral.MongoAtlasEnabled == 1 {
-			msg_mongo_count += 1
-
-			// Flush/insert
-			// Cast a byte string to BSon
-			// https://stackoverflow.com/questions/39785289/how-to-marshal-json-string-to-bson-document-for-writing-to-mongodb
-			// this way we don't need to care what the source structure is, it is all cast and inserted into the defined collection.
-
-			basketdoc, err := JsonToBson(json_SalesBasket)
-			if err != nil {
-				grpcLog.Errorln("Oops, we had a problem JsonToBson converting the payload, ", err)
-
-			}
-
-			paymentdoc, err := JsonToBson(json_Payment)
-			if err != nil {
-				grpcLog.Errorln("Oops, we had a problem JsonToBson converting the payload, ", err)
-
-			}
-
-			// Single Record inserts
-			if vMongodb.Batch_size == 1 {
-
-				// Sales Basket
-
-				// Time to get this into the MondoDB Collection
-				result, err := basketcol.InsertOne(context.TODO(), basketdoc)
-				if err != nil {
-					grpcLog.Errorln(fmt.Sprintf("Oops, we had a problem inserting (I1) the document, %s", err))
-
-				}
-
-				if vGeneral.Debuglevel >= 2 {
-					// When you run this file, it should print:
-					// Document inserted with ID: ObjectID("...")
-					grpcLog.Infoln("Mongo Sales Basket Doc inserted with ID: ", result.InsertedID, "\n")
-
-				}
-				if vGeneral.Debuglevel >= 3 {
-					// prettyJSON takes a string which is actually JSON and makes it's pretty, and prints it.
-					prettyJSON(string(json_SalesBasket))
-
-				}
-
-				// Payment
-
-				// Time to get this into the MondoDB Collection
-				result, err = paymentcol.InsertOne(context.TODO(), paymentdoc)
-				if err != nil {
-					grpcLog.Errorln(fmt.Sprintf("Oops, we had a problem inserting (I1) the document, %s", err))
-
-				}
-
-				if vGeneral.Debuglevel >= 2 {
-					// When you run this file, it should print:
-					// Document inserted with ID: ObjectID("...")
-					grpcLog.Infoln("Mongo Payment Doc inserted with ID: ", result.InsertedID, "\n")
-
-				}
-				if vGeneral.Debuglevel >= 3 {
-					// prettyJSON takes a string which is actually JSON and makes it's pretty, and prints it.
-					prettyJSON(string(json_Payment))
-
-				}
-
-			} else {
-
-				// Sales Basket
-				basketdocs[msg_mongo_count-1] = basketdoc
-				paymentdocs[msg_mongo_count-1] = paymentdoc
-
-				if msg_mongo_count%vMongodb.Batch_size == 0 {
-
-					// Time to get this into the MondoDB Collection
-
-					// Sales Basket
-					_, err = basketcol.InsertMany(context.TODO(), basketdocs)
-					if err != nil {
-						grpcLog.Errorln(fmt.Sprintf("Oops, we had a problem inserting (IM) the document, %s", err))
-
-					}
-					if vGeneral.Debuglevel >= 2 {
-						grpcLog.Infoln("Mongo Sale Basket Docs inserted: ", msg_mongo_count)
-
-					}
-
-					// Payment
-					_, err = paymentcol.InsertMany(context.TODO(), paymentdocs)
-					if err != nil {
-						grpcLog.Errorln(fmt.Sprintf("Oops, we had a problem inserting (IM) the document, %s", err))
-
-					}
-					if vGeneral.Debuglevel >= 2 {
-						grpcLog.Infoln("Mongo Payment Docs inserted: ", msg_mongo_count)
-
-					}
-
-					msg_mongo_count = 0
-
-				}
-
-			}
-
+		// When the next sale happens, the traffic model decides the pace and basket composition
+		traffic, now, ok := clock.next()
+		if !ok {
+			break
 		}
 
-		// Save multiple Basket docs and Payment docs to a single basket file and single payment file for the run
-		if vGeneral.Json_to_file == 1 {
-
-			if vGeneral.Debuglevel >= 2 {
-				grpcLog.Info("")
-				grpcLog.Info("JSON to File Flow")
-
-			}
-
-			// Basket
-			pretty_basket, err := json.MarshalIndent(pb_Basket, "", " ")
-			if err != nil {
-				grpcLog.Errorln(fmt.Sprintf("pretty_basket MarshalIndent error %s", err))
-
-			}
-
-			if _, err = f_basket.WriteString(string(pretty_basket) + ",\n"); err != nil {
-				grpcLog.Errorln(fmt.Sprintf("os.WriteString error %s", err))
-
-			}
-
-			// Payment
-			pretty_pmnt, err := json.MarshalIndent(pb_Payment, "", " ")
-			if err != nil {
-				grpcLog.Errorln(fmt.Sprintf("pretty_pmnt MarshalIndent error %s", err))
-
-			}
-
-			if _, err = f_pmnt.WriteString(string(pretty_pmnt) + ",\n"); err != nil {
-				grpcLog.Errorln(fmt.Sprintf("pretty_pmnt os.WriteString error %s", err))
-
-			}
-
+		// One sale, or a walk out
+		if !sell(sinks, traffic, now, !clock.simulated()) {
+			walkouts++
 		}
 
 		if vGeneral.Debuglevel > 1 {
 			grpcLog.Infoln("Total Time                    :", time.Since(txnStart).Seconds(), "Sec")
 
 		}
+	}
 
-		// used to slow the data production/posting to kafka and safe to file system down.
-		if vGeneral.Sleep > 0 {
-			n := rand.Intn(vGeneral.Sleep) // if vGeneral.sleep = 1000, then n will be random value of 0 -> 1000  aka 0 and 1 second
-			if vGeneral.Debuglevel >= 2 {
-				grpcLog.Infof("Going to sleep for            : %d Milliseconds\n", n)
+	// The online order steps still ahead, so every order's lifecycle is complete
+	sinks.postOrderEvents(varOrder.drain())
 
-			}
-			time.Sleep(time.Duration(n) * time.Millisecond)
-		}
-
-	}
+	// Flush the Kafka queue and trailing Mongo batch, before we report.
+	sinks.close()
 
 	grpcLog.Infoln("")
 	grpcLog.Infoln("**** DONE Processing ****")
@@ -1245,26 +790,20 @@ func runLoader(arg string) {
 
 	vEnd := time.Now()
 	vElapse := vEnd.Sub(vStart)
+	latency.stop()
+
 	grpcLog.Infoln("Start                         : ", vStart)
 	grpcLog.Infoln("End                           : ", vEnd)
 	grpcLog.Infoln("Elapsed Time (Seconds)        : ", vElapse.Seconds())
-	grpcLog.Infoln("Records Processed             : ", vGeneral.Testsize)
-	grpcLog.Infoln(fmt.Sprintf("                              :  %.3f Txns/Second", float64(vGeneral.Testsize)/vElapse.Seconds()))
+	grpcLog.Infoln("Run Id                        : ", runId)
+	grpcLog.Infoln("Records Processed             : ", count)
+	if walkouts > 0 {
+		grpcLog.Infoln("Walk outs (out of stock)      : ", walkouts)
+	}
+	varFault.report()
+	grpcLog.Infoln(fmt.Sprintf("                              :  %.3f Txns/Second", float64(count)/vElapse.Seconds()))
+	latency.report()
 
 	grpcLog.Infoln("")
 
 } // runLoader()
-
-func main() {
-
-	var arg string
-
-	grpcLog.Info("****** Starting           *****")
-
-	arg = os.Args[1]
-
-	runLoader(arg)
-
-	grpcLog.Info("****** Completed          *****")
-
-}
@@ -0,0 +1,315 @@
+/*****************************************************************************
+*
+*	File			: mongo.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Direct MongoDB inserts of the created documents, bypassing Kafka.
+*					: The document modelling mode (*_mongo.json Modelling) decides how baskets and payments are laid out:
+*					:	separate	- baskets into Basketcollection, payments into Paymentcollection (default)
+*					:	embedded	- one combined sale document, payment embedded, into Salescollection
+*					:	upsert		- basket into Basketcollection, payment then upserted into that basket document
+*					: Inventory movements go into Inventorycollection, price changes into Pricecollection, online order
+*					: events into Ordercollection and anomaly labels into Labelcollection, whatever the modelling.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cmd/types"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+const (
+	modelSeparate = "separate"
+	modelEmbedded = "embedded"
+	modelUpsert   = "upsert"
+)
+
+// Connect to and Ping the Mongo store, the caller is responsible for the Disconnect.
+func connectMongo(props types.TMongodb) (*mongo.Client, error) {
+
+	serverAPI := options.ServerAPI(options.ServerAPIVersion1)
+
+	opts := options.Client().ApplyURI(props.Uri).SetServerAPIOptions(serverAPI)
+
+	grpcLog.Infoln("* MongoDB URI Constructed: ", props.Uri)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	Mongoclient, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	grpcLog.Infoln("* MongoDB Client Connected")
+
+	// Ping the primary
+	if err := Mongoclient.Ping(ctx, readpref.Primary()); err != nil {
+		Mongoclient.Disconnect(context.TODO())
+		return nil, fmt.Errorf("there was a error creating the Client object, Ping failed: %w", err)
+	}
+	grpcLog.Infoln("* MongoDB Client Pinged")
+
+	return Mongoclient, nil
+}
+
+type mongoSink struct {
+	modelling   string
+	batchSize   int
+	basketcol   *mongo.Collection
+	paymentcol  *mongo.Collection
+	salescol    *mongo.Collection
+	basketdocs  []interface{}
+	paymentdocs []mongo.WriteModel
+	queues      map[string]*tpMongoQueue // inventory, price, orders and labels, those that have a collection
+}
+
+// The documents of a kind queued for their collection, inserted per batchSize.
+type tpMongoQueue struct {
+	kind string
+	col  *mongo.Collection
+	docs []interface{}
+}
+
+func newMongoSink(appLabDatabase *mongo.Database, props types.TMongodb) (*mongoSink, error) {
+
+	s := &mongoSink{
+		modelling: props.Modelling,
+		batchSize: props.Batch_size,
+	}
+
+	if s.modelling == "" {
+		s.modelling = modelSeparate
+	}
+	if s.batchSize < 1 {
+		s.batchSize = 1
+	}
+
+	switch s.modelling {
+	case modelSeparate:
+		s.basketcol = appLabDatabase.Collection(props.Basketcollection)
+		s.paymentcol = appLabDatabase.Collection(props.Paymentcollection)
+
+	case modelEmbedded:
+		if props.Salescollection == "" {
+			return nil, fmt.Errorf("Modelling %s requires a Salescollection", modelEmbedded)
+		}
+		s.salescol = appLabDatabase.Collection(props.Salescollection)
+
+	case modelUpsert:
+		s.basketcol = appLabDatabase.Collection(props.Basketcollection)
+
+	default:
+		return nil, fmt.Errorf("unknown Modelling %q, expected %s, %s or %s", props.Modelling, modelSeparate, modelEmbedded, modelUpsert)
+	}
+
+	s.queues = make(map[string]*tpMongoQueue)
+	for kind, collection := range map[string]string{
+		"inventory": props.Inventorycollection,
+		"price":     props.Pricecollection,
+		"orders":    props.Ordercollection,
+		"labels":    props.Labelcollection,
+	} {
+		if collection != "" {
+			s.queues[kind] = &tpMongoQueue{kind: kind, col: appLabDatabase.Collection(collection)}
+		}
+	}
+
+	return s, nil
+}
+
+// Queue the basket and its payment for insert, documents are written once batchSize baskets have been queued. The
+// documents are the JSON as posted, faults and all, see faults.go, invoiceNumber is the sale's own, for the upsert.
+func (s *mongoSink) write(invoiceNumber string, json_SalesBasket []byte, json_Payment []byte) {
+
+	// Cast a byte string to BSon
+	// https://stackoverflow.com/questions/39785289/how-to-marshal-json-string-to-bson-document-for-writing-to-mongodb
+	// this way we don't need to care what the source structure is, it is all cast and inserted into the defined collection.
+	switch s.modelling {
+	case modelEmbedded:
+		// Combined sale document, the basket with its payment embedded
+		basketdoc, err := JsonToBson(json_SalesBasket)
+		if err != nil {
+			grpcLog.Errorln("Oops, we had a problem JsonToBson converting the payload, ", err)
+			return
+
+		}
+
+		paymentdoc, err := JsonToBson(json_Payment)
+		if err != nil {
+			grpcLog.Errorln("Oops, we had a problem JsonToBson converting the payload, ", err)
+			return
+
+		}
+
+		var saledoc bson.D
+		if err := bson.Unmarshal(basketdoc, &saledoc); err != nil {
+			grpcLog.Errorln("Oops, we had a problem building the sale document, ", err)
+			return
+
+		}
+		s.basketdocs = append(s.basketdocs, append(saledoc, bson.E{Key: "payment", Value: bson.Raw(paymentdoc)}))
+
+	case modelUpsert:
+		basketdoc, err := JsonToBson(json_SalesBasket)
+		if err != nil {
+			grpcLog.Errorln("Oops, we had a problem JsonToBson converting the payload, ", err)
+			return
+
+		}
+
+		paymentdoc, err := JsonToBson(json_Payment)
+		if err != nil {
+			grpcLog.Errorln("Oops, we had a problem JsonToBson converting the payload, ", err)
+			return
+
+		}
+
+		s.basketdocs = append(s.basketdocs, basketdoc)
+		s.paymentdocs = append(s.paymentdocs, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"invoiceNumber": invoiceNumber}).
+			SetUpdate(bson.M{"$set": bson.M{"payment": bson.Raw(paymentdoc)}}).
+			SetUpsert(true))
+
+	default:
+		basketdoc, err := JsonToBson(json_SalesBasket)
+		if err != nil {
+			grpcLog.Errorln("Oops, we had a problem JsonToBson converting the payload, ", err)
+			return
+
+		}
+
+		paymentdoc, err := JsonToBson(json_Payment)
+		if err != nil {
+			grpcLog.Errorln("Oops, we had a problem JsonToBson converting the payload, ", err)
+			return
+
+		}
+
+		s.basketdocs = append(s.basketdocs, basketdoc)
+		s.paymentdocs = append(s.paymentdocs, mongo.NewInsertOneModel().SetDocument(paymentdoc))
+
+	}
+
+	if len(s.basketdocs) >= s.batchSize {
+		s.flush()
+
+	}
+}
+
+// Queue a document of the kind, inventory, price, orders or labels, for insert, if we have a collection for them,
+// written per batchSize documents.
+func (s *mongoSink) writeDoc(kind string, json_Doc []byte) {
+
+	q, ok := s.queues[kind]
+	if !ok {
+		return
+	}
+
+	doc, err := JsonToBson(json_Doc)
+	if err != nil {
+		grpcLog.Errorln("Oops, we had a problem JsonToBson converting the payload, ", err)
+		return
+
+	}
+	q.docs = append(q.docs, doc)
+
+	if len(q.docs) >= s.batchSize {
+		q.flush()
+
+	}
+}
+
+func (q *tpMongoQueue) flush() {
+
+	if len(q.docs) == 0 {
+		return
+	}
+
+	_, err := q.col.InsertMany(context.TODO(), q.docs)
+	if err != nil {
+		grpcLog.Errorln(fmt.Sprintf("Oops, we had a problem inserting (IM) the %s documents, %s", q.kind, err))
+
+	}
+	if vGeneral.Debuglevel >= 2 {
+		grpcLog.Infoln(fmt.Sprintf("Mongo %s Docs inserted: ", q.kind), len(q.docs))
+
+	}
+
+	q.docs = q.docs[:0]
+}
+
+// Write whatever has been queued, also called at the end of the run so a trailing partial batch is not lost.
+func (s *mongoSink) flush() {
+
+	for _, q := range s.queues {
+		q.flush()
+	}
+
+	if len(s.basketdocs) == 0 {
+		return
+	}
+
+	// Sales Basket, or the combined Sale document when embedded
+	col := s.basketcol
+	if s.modelling == modelEmbedded {
+		col = s.salescol
+	}
+
+	if len(s.basketdocs) == 1 {
+		result, err := col.InsertOne(context.TODO(), s.basketdocs[0])
+		if err != nil {
+			grpcLog.Errorln(fmt.Sprintf("Oops, we had a problem inserting (I1) the document, %s", err))
+
+		} else if vGeneral.Debuglevel >= 2 {
+			// Document inserted with ID: ObjectID("...")
+			grpcLog.Infoln("Mongo Sales Basket Doc inserted with ID: ", result.InsertedID)
+
+		}
+
+	} else {
+		_, err := col.InsertMany(context.TODO(), s.basketdocs)
+		if err != nil {
+			grpcLog.Errorln(fmt.Sprintf("Oops, we had a problem inserting (IM) the document, %s", err))
+
+		}
+		if vGeneral.Debuglevel >= 2 {
+			grpcLog.Infoln("Mongo Sale Basket Docs inserted: ", len(s.basketdocs))
+
+		}
+	}
+
+	// Payment, either inserted into the payment collection or upserted into the basket it belongs to.
+	if len(s.paymentdocs) > 0 {
+		col = s.paymentcol
+		if s.modelling == modelUpsert {
+			col = s.basketcol
+		}
+
+		_, err := col.BulkWrite(context.TODO(), s.paymentdocs, options.BulkWrite().SetOrdered(true))
+		if err != nil {
+			grpcLog.Errorln(fmt.Sprintf("Oops, we had a problem writing (BW) the payment document, %s", err))
+
+		}
+		if vGeneral.Debuglevel >= 2 {
+			grpcLog.Infoln("Mongo Payment Docs written: ", len(s.paymentdocs))
+
+		}
+	}
+
+	s.basketdocs = s.basketdocs[:0]
+	s.paymentdocs = s.paymentdocs[:0]
+
+}
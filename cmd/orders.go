@@ -0,0 +1,275 @@
+/*****************************************************************************
+*
+*	File			: orders.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Online orders, from the seed "online", without one online terminals sell as any other, as before:
+*
+*					: "online": {"shippingFee": 65, "freeShippingOver": 500, "cancelRate": 0.03,
+*					:            "carriers": ["Courier Guy", "DHL"],
+*					:            "delays": {"picked": 90, "shipped": 240, "delivered": 1440}}
+*
+*					: A basket sold at an online terminal, see terminals.go, is an order, it gets a delivery address, the
+*					: customer's or a made up one in the store's city, and a shipping fee, in its total, free from
+*					: freeShippingOver. Each order then goes through its lifecycle, one order event per step:
+*
+*					:	placed		- at the sale
+*					:	paid		- at the payment
+*					:	picked		- delays picked minutes (on average) after paid
+*					:	shipped		- delays shipped minutes after picked, with a carrier and tracking number
+*					:	delivered	- delays delivered minutes after shipped
+*					:	cancelled	- cancelRate of the orders, after paid or picked, instead of the steps that follow
+*
+*					: The events are posted to OrderTopicname / Ordercollection and the json_save orders file as the
+*					: run's clock reaches them, backfill's too, those still ahead as the run ends are posted then.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+
+	"cmd/types"
+)
+
+const (
+	orderPlaced    = "placed"
+	orderPaid      = "paid"
+	orderPicked    = "picked"
+	orderShipped   = "shipped"
+	orderDelivered = "delivered"
+	orderCancelled = "cancelled"
+)
+
+var defaultCarriers = []string{"Courier Guy", "DHL", "PostNet"}
+
+var cancelReasons = []string{"customer request", "out of stock", "payment review", "address undeliverable"}
+
+// An order event, due at
+type tpOrderEvent struct {
+	at    time.Time
+	event *types.PBOrderEvent
+}
+
+type tpOrders struct {
+	online  types.TPOnline
+	pending []tpOrderEvent // by when they are due
+}
+
+func newOrders(seed types.TPSeed) *tpOrders {
+	return &tpOrders{online: seed.Online}
+}
+
+func (o *tpOrders) enabled() bool {
+	return o.online.Delays.Delivered > 0
+}
+
+// Give an online basket its delivery address and shipping fee, the fee goes on the total.
+func (o *tpOrders) ship(pb_Basket *types.Pb_Basket, store types.TPStoreStruct, customer *types.Customer) {
+
+	if !o.enabled() || pb_Basket.TerminalType != terminalOnline {
+		return
+	}
+
+	fee := varCurr.price(o.online.ShippingFee, pb_Basket.Currency)
+	if o.online.FreeShippingOver > 0 && pb_Basket.Total >= varCurr.price(o.online.FreeShippingOver, pb_Basket.Currency) {
+		fee = 0
+	}
+	pb_Basket.ShippingFee = fee
+	pb_Basket.Total = toFixed(pb_Basket.Total+fee, 2)
+
+	recipient := gofakeit.Name()
+	if customer != nil {
+		recipient = customer.Name
+	}
+	city := store.City
+	if city == "" {
+		city = store.Name
+	}
+
+	pb_Basket.Delivery = &types.Address{
+		Recipient:  recipient,
+		Street:     gofakeit.Street(),
+		City:       city,
+		PostalCode: gofakeit.Zip(),
+		Region:     store.Region,
+	}
+}
+
+// A step's delay, 0.5..1.5 times its average minutes
+func orderDelay(minutes float64) time.Duration {
+	return time.Duration(minutes * (0.5 + rand.Float64()) * float64(time.Minute))
+}
+
+// Schedule the order's lifecycle, from the basket and its payment, the events in the store's local time. A basket
+// without a delivery address, a refund or card testing, see anomalies.go, is not an order.
+func (o *tpOrders) place(pb_Basket *types.Pb_Basket, pb_Payment *types.Pb_Payment) {
+
+	if !o.enabled() || pb_Basket.TerminalType != terminalOnline || pb_Basket.Delivery == nil {
+		return
+	}
+
+	var store types.TPStoreStruct
+	for _, s := range varSeed.Stores {
+		if s.Id == pb_Basket.Store.Id {
+			store = s
+		}
+	}
+
+	epoch := func(ms string) time.Time {
+		n, _ := strconv.ParseInt(ms, 10, 64)
+		return storeTime(store, time.UnixMilli(n))
+	}
+
+	customerId := ""
+	if pb_Basket.Customer != nil {
+		customerId = pb_Basket.Customer.Id
+	}
+
+	sequence := int32(0)
+	add := func(status string, at time.Time, carrier string, tracking string, reason string) {
+		sequence++
+		o.schedule(tpOrderEvent{at: at, event: &types.PBOrderEvent{
+			EventId:        uuid.New().String(),
+			OrderId:        pb_Basket.InvoiceNumber,
+			Sequence:       sequence,
+			Status:         status,
+			EventDateTime:  formatTimestamp(at),
+			EventTimestamp: fmt.Sprint(at.UnixMilli()),
+			StoreId:        store.Id,
+			StoreName:      store.Name,
+			CustomerId:     customerId,
+			Total:          pb_Basket.Total,
+			Currency:       pb_Basket.Currency,
+			Carrier:        carrier,
+			TrackingNumber: tracking,
+			DeliveryCity:   pb_Basket.Delivery.GetCity(),
+			Reason:         reason,
+		}})
+	}
+
+	add(orderPlaced, epoch(pb_Basket.SaleTimestamp), "", "", "")
+	at := epoch(pb_Payment.PayTimestamp)
+	add(orderPaid, at, "", "", "")
+
+	// Cancelled orders go after paid (0) or picked (1)
+	cancelAfter := -1
+	if rand.Float64() < o.online.CancelRate {
+		cancelAfter = rand.Intn(2)
+	}
+
+	at = at.Add(orderDelay(o.online.Delays.Picked))
+	if cancelAfter == 0 {
+		add(orderCancelled, at, "", "", cancelReasons[rand.Intn(len(cancelReasons))])
+		return
+	}
+	add(orderPicked, at, "", "", "")
+
+	at = at.Add(orderDelay(o.online.Delays.Shipped))
+	if cancelAfter == 1 {
+		add(orderCancelled, at, "", "", cancelReasons[rand.Intn(len(cancelReasons))])
+		return
+	}
+
+	carriers := o.online.Carriers
+	if len(carriers) == 0 {
+		carriers = defaultCarriers
+	}
+	carrier := carriers[rand.Intn(len(carriers))]
+
+	var initials string
+	for _, word := range strings.Fields(carrier) {
+		initials += strings.ToUpper(word[:1])
+	}
+	tracking := fmt.Sprintf("%s%010d", initials, rand.Int63n(10000000000))
+
+	add(orderShipped, at, carrier, tracking, "")
+	add(orderDelivered, at.Add(orderDelay(o.online.Delays.Delivered)), carrier, tracking, "")
+}
+
+// Queue the event, in order of when it is due, events due at the same time in the order scheduled.
+func (o *tpOrders) schedule(e tpOrderEvent) {
+
+	i := sort.Search(len(o.pending), func(i int) bool { return o.pending[i].at.After(e.at) })
+	o.pending = append(o.pending, tpOrderEvent{})
+	copy(o.pending[i+1:], o.pending[i:])
+	o.pending[i] = e
+}
+
+// The events due by now, for the sinks.
+func (o *tpOrders) due(now time.Time) []*types.PBOrderEvent {
+
+	var events []*types.PBOrderEvent
+	n := 0
+	for ; n < len(o.pending) && !o.pending[n].at.After(now); n++ {
+		events = append(events, o.pending[n].event)
+	}
+	o.pending = o.pending[n:]
+	return events
+}
+
+// Every event still pending, as the run ends.
+func (o *tpOrders) drain() []*types.PBOrderEvent {
+
+	var events []*types.PBOrderEvent
+	for _, e := range o.pending {
+		events = append(events, e.event)
+	}
+	o.pending = nil
+	return events
+}
+
+// The fees, cancelRate and delays are sane, and some store has an online terminal to take the orders.
+func validateOnline(seed types.TPSeed) []string {
+
+	var problems []string
+
+	online := seed.Online
+	if online.ShippingFee < 0 {
+		problems = append(problems, fmt.Sprintf("seed online shippingFee must be >= 0, got %g", online.ShippingFee))
+	}
+	if online.FreeShippingOver < 0 {
+		problems = append(problems, fmt.Sprintf("seed online freeShippingOver must be >= 0 (0 => never), got %g", online.FreeShippingOver))
+	}
+	if online.CancelRate < 0 || online.CancelRate > 1 {
+		problems = append(problems, fmt.Sprintf("seed online cancelRate must be between 0 and 1, got %g", online.CancelRate))
+	}
+
+	delays := online.Delays
+	if delays.Picked < 0 || delays.Shipped < 0 || delays.Delivered < 0 {
+		problems = append(problems, fmt.Sprintf("seed online delays must be >= 0 minutes, got picked %g, shipped %g and delivered %g", delays.Picked, delays.Shipped, delays.Delivered))
+	}
+
+	set := online.ShippingFee != 0 || online.FreeShippingOver != 0 || online.CancelRate != 0 || len(online.Carriers) > 0 || delays.Picked != 0 || delays.Shipped != 0
+	if set && delays.Delivered == 0 {
+		problems = append(problems, "seed online delays delivered must be > 0 minutes, it switches online orders on")
+	}
+
+	if delays.Delivered > 0 {
+		found := false
+		for _, store := range seed.Stores {
+			for _, terminal := range storeTerminals(store) {
+				if terminal.Type == terminalOnline {
+					found = true
+				}
+			}
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("seed online orders need a store with an %s terminal, none has one", terminalOnline))
+		}
+	}
+
+	return problems
+}
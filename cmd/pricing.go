@@ -0,0 +1,420 @@
+/*****************************************************************************
+*
+*	File			: pricing.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Price changes during a run, from the seed "pricing", without one prices are as per the seed, as before:
+*
+*					: "pricing": {"inflation": 0.06,
+*					:             "changes": [{"name": "Black Friday", "from": "2026-11-27", "to": "2026-11-29",
+*					:                          "categories": ["Beverage"], "percent": -20}],
+*					:             "markdowns": {"chance": 0.01, "percent": 30, "days": 3},
+*					:             "priceWars": {"chance": 0.05, "categories": ["Coffee"], "percent": 15, "days": 2}}
+*
+*					: Prices are worked out once a day, in the app Timezone, as the run's clock crosses into it:
+*					: the seed price, drifted by inflation (yearly, a step on the 1st of every month) since the run started, times the scheduled
+*					: changes on that day, and the markdowns (per product) and price wars (per category) rolled that
+*					: day or still running. When a change, markdown or price war ends, the price goes back.
+*
+*					: A live run is over long before a day is, "interval": <minutes> works the prices out every interval
+*					: of the run instead, each interval rolls the markdowns and price wars and steps inflation up a
+*					: month's worth, so the run's baskets sell at the changes. The cuts last days, or "minutes".
+*
+*					: Every price, as the run starts, and every change after, is a price change event, posted to
+*					: PriceTopicname / Pricecollection and the json_save price file, ahead of the baskets sold at it,
+*					: which makes a price table to join the sales to. A refreshed seed, see seedsource.go, changes the
+*					: seed prices from then on.
+*
+*					: Stores at a price tier, see profiles.go, or in another currency, see currency.go, sell at prices
+*					: of their own, a price list, each change is an event per price list too, at its tier and in its
+*					: currency, with the stores that sell at it.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"cmd/types"
+)
+
+const (
+	priceOpening   = "opening"
+	priceInflation = "inflation"
+	priceSeed      = "seed"
+	priceRestored  = "restored"
+	priceMarkdown  = "markdown"
+	priceWar       = "price_war"
+)
+
+// A random markdown or price war, percent off the product or category up to until
+type tpPriceCut struct {
+	name     string
+	product  string
+	category string
+	percent  float64
+	until    time.Time
+}
+
+type tpPricing struct {
+	pricing types.TPPricing
+	base    map[string]float64 // product id => seed price
+	current map[string]float64 // product id => price now, absent => not opened yet
+	cause   map[string]string  // product id => what the price now is down to, blank => the seed price (and inflation)
+	seeded  map[string]bool    // product id => the seed price changed since the last change
+	start   time.Time          // the run's first price update, inflation is from here
+	step    time.Time          // day, or interval, the prices were last worked out for, zero => due
+	rolled  time.Time          // day, or interval, the markdowns and price wars were last rolled for
+	cuts    []tpPriceCut
+	changes []*types.PBPriceChange
+}
+
+func newPricing(seed types.TPSeed) *tpPricing {
+
+	p := &tpPricing{
+		pricing: seed.Pricing,
+		base:    make(map[string]float64),
+		current: make(map[string]float64),
+		cause:   make(map[string]string),
+		seeded:  make(map[string]bool),
+	}
+	for _, product := range seed.Products {
+		p.base[product.Id] = product.Price
+	}
+	return p
+}
+
+// The pricing for a refreshed seed, the prices, cuts and the run's first day carry over, the new seed prices apply
+// from the next sale.
+func (p *tpPricing) reseed(seed types.TPSeed) *tpPricing {
+
+	fresh := newPricing(seed)
+	for id, price := range fresh.base {
+		if old, ok := p.base[id]; ok && old != price {
+			fresh.seeded[id] = true
+		}
+	}
+	fresh.current = p.current
+	fresh.cause = p.cause
+	fresh.start = p.start
+	fresh.rolled = p.rolled
+	fresh.cuts = p.cuts
+	fresh.changes = p.changes
+	return fresh
+}
+
+func (p *tpPricing) enabled() bool {
+	return p.pricing.Inflation != 0 || len(p.pricing.Changes) > 0 || p.pricing.Markdowns.Chance > 0 || p.pricing.PriceWars.Chance > 0
+}
+
+// Roll the day's, or interval's, markdowns and price wars, a product or category already cut is left be.
+func (p *tpPricing) roll(seed types.TPSeed, step time.Time) {
+
+	cut := make(map[string]bool)
+	for _, c := range p.cuts {
+		cut[c.product+"/"+c.category] = true
+	}
+
+	until := func(cuts types.TPRandomPriceCuts) time.Time {
+		if cuts.Minutes > 0 {
+			return step.Add(time.Duration(cuts.Minutes) * time.Minute)
+		}
+		if cuts.Days < 1 {
+			return step.AddDate(0, 0, 1)
+		}
+		return step.AddDate(0, 0, cuts.Days)
+	}
+
+	markdowns := p.pricing.Markdowns
+	if markdowns.Chance > 0 {
+		for _, product := range seed.Products {
+			if rand.Float64() < markdowns.Chance && !cut[product.Id+"/"] {
+				p.cuts = append(p.cuts, tpPriceCut{name: priceMarkdown, product: product.Id, percent: markdowns.Percent, until: until(markdowns)})
+			}
+		}
+	}
+
+	wars := p.pricing.PriceWars
+	if wars.Chance > 0 && rand.Float64() < wars.Chance {
+		categories := wars.Categories
+		if len(categories) == 0 {
+			seen := make(map[string]bool)
+			for _, product := range seed.Products {
+				if !seen[product.Category] {
+					seen[product.Category] = true
+					categories = append(categories, product.Category)
+				}
+			}
+		}
+		if len(categories) > 0 {
+			category := categories[rand.Intn(len(categories))]
+			if !cut["/"+category] {
+				p.cuts = append(p.cuts, tpPriceCut{name: priceWar + " " + category, category: category, percent: wars.Percent, until: until(wars)})
+			}
+		}
+	}
+}
+
+// The stores that sell at the same price tier in the same currency
+type tpPriceList struct {
+	name     string // blank => the seed prices
+	tier     float64
+	currency string
+	stores   []string
+}
+
+// The price lists of the seed stores, the seed prices first, for the stores at no price tier in the app Currency.
+func priceLists(seed types.TPSeed) []*tpPriceList {
+
+	lists := []*tpPriceList{{tier: 1, currency: vGeneral.Currency}}
+	byName := map[string]*tpPriceList{"": lists[0]}
+
+	for _, store := range seed.Stores {
+		tier, profile := varProf.tier(store)
+		currency := storeCurrency(store)
+
+		var parts []string
+		if profile != "" {
+			parts = append(parts, profile)
+		}
+		if currency != vGeneral.Currency {
+			parts = append(parts, currency)
+		}
+		name := strings.Join(parts, "/")
+		if name == "" {
+			continue
+		}
+
+		list, ok := byName[name]
+		if !ok {
+			list = &tpPriceList{name: name, tier: tier, currency: currency}
+			byName[name] = list
+			lists = append(lists, list)
+		}
+		list.stores = append(list.stores, store.Id)
+	}
+	return lists
+}
+
+// The seed price at the price list's tier and in its currency, as the stores sell at it.
+func (l *tpPriceList) price(price float64) float64 {
+
+	if l.tier != 1 {
+		price = toFixed(price*l.tier, 2)
+	}
+	return varCurr.price(price, l.currency)
+}
+
+// The start of the pricing step t is in, its midnight or, with an interval, the interval of the run.
+func (p *tpPricing) stepOf(t time.Time) time.Time {
+
+	if p.pricing.Interval > 0 {
+		interval := time.Duration(p.pricing.Interval) * time.Minute
+		return p.start.Add(t.Sub(p.start) / interval * interval)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// Work out the prices at t, if not done yet for its day, or interval, setting them on the seed products and recording
+// the changes.
+func (p *tpPricing) update(seed *types.TPSeed, t time.Time) {
+
+	if !p.enabled() {
+		return
+	}
+
+	t = t.In(zone(""))
+	if p.start.IsZero() {
+		p.start = t
+	}
+	step := p.stepOf(t)
+	if step.Equal(p.step) {
+		return
+	}
+	p.step = step
+	day := t.Format(trafficDate)
+
+	// A new day, or interval, changes as from its start, a refreshed seed as from now
+	at := t
+	if !p.rolled.Equal(step) {
+		p.rolled = step
+		at = step
+
+		var cuts []tpPriceCut
+		for _, c := range p.cuts {
+			if c.until.After(step) {
+				cuts = append(cuts, c)
+			}
+		}
+		p.cuts = cuts
+		p.roll(*seed, step)
+	}
+
+	// Inflation steps up on the 1st of every month, or every interval, a month's worth
+	months := (t.Year()-p.start.Year())*12 + int(t.Month()) - int(p.start.Month())
+	if p.pricing.Interval > 0 {
+		months = int(step.Sub(p.start) / (time.Duration(p.pricing.Interval) * time.Minute))
+	}
+	drift := math.Pow(1+p.pricing.Inflation, float64(months)/12)
+
+	lists := priceLists(*seed)
+
+	for i, product := range seed.Products {
+		factor := 1.0
+		var causes []string
+		for _, change := range p.pricing.Changes {
+			if (change.From == "" || day >= change.From) && (change.To == "" || day <= change.To) &&
+				(contains(change.Products, product.Id) || contains(change.Categories, product.Category)) {
+				factor *= 1 + change.Percent/100
+				causes = append(causes, change.Name)
+			}
+		}
+		for _, c := range p.cuts {
+			if c.product == product.Id || c.category == product.Category {
+				factor *= 1 - c.percent/100
+				causes = append(causes, c.name)
+			}
+		}
+		cause := strings.Join(causes, "+")
+
+		price := toFixed(p.base[product.Id]*drift*factor, 2)
+		old, opened := p.current[product.Id]
+
+		var reason string
+		switch {
+		case !opened:
+			reason = priceOpening
+		case price == old:
+		case cause != p.cause[product.Id] && cause == "":
+			reason = priceRestored
+		case cause != p.cause[product.Id]:
+			reason = cause
+		case p.seeded[product.Id]:
+			reason = priceSeed
+		default:
+			reason = priceInflation
+		}
+
+		seed.Products[i].Price = price
+		p.current[product.Id] = price
+		p.cause[product.Id] = cause
+		if reason == "" {
+			continue
+		}
+		delete(p.seeded, product.Id)
+
+		for _, list := range lists {
+			p.changes = append(p.changes, &types.PBPriceChange{
+				ChangeId:        uuid.New().String(),
+				ChangeDateTime:  formatTimestamp(at),
+				ChangeTimestamp: fmt.Sprint(at.UnixMilli()),
+				ProductId:       product.Id,
+				ProductName:     product.Name,
+				Category:        product.Category,
+				OldPrice:        list.price(old),
+				NewPrice:        list.price(price),
+				Currency:        list.currency,
+				Reason:          reason,
+				PriceList:       list.name,
+				PriceTier:       list.tier,
+				StoreIds:        list.stores,
+			})
+		}
+	}
+}
+
+// The price changes since the last drain, for the sinks.
+func (p *tpPricing) drain() []*types.PBPriceChange {
+
+	changes := p.changes
+	p.changes = nil
+	return changes
+}
+
+// Inflation, the scheduled changes and the markdowns and price wars are sane, of seed products and categories.
+func validatePricing(seed types.TPSeed) []string {
+
+	var problems []string
+
+	pricing := seed.Pricing
+	if pricing.Inflation <= -1 || pricing.Inflation > 1 {
+		problems = append(problems, fmt.Sprintf("seed pricing inflation must be a yearly fraction between -1 and 1, ie 0.06, got %g", pricing.Inflation))
+	}
+	if pricing.Interval < 0 {
+		problems = append(problems, fmt.Sprintf("seed pricing interval must be >= 0 minutes (0 => once a day), got %d", pricing.Interval))
+	}
+
+	products := make(map[string]bool)
+	categories := make(map[string]bool)
+	for _, product := range seed.Products {
+		products[product.Id] = true
+		categories[product.Category] = true
+	}
+
+	for i, change := range pricing.Changes {
+		name := change.Name
+		if name == "" {
+			problems = append(problems, fmt.Sprintf("seed pricing change %d has no name", i))
+			name = fmt.Sprint(i)
+		}
+		if change.Percent == 0 || change.Percent <= -100 {
+			problems = append(problems, fmt.Sprintf("seed pricing change %s percent must be > -100 and not 0, got %g", name, change.Percent))
+		}
+		if len(change.Products) == 0 && len(change.Categories) == 0 {
+			problems = append(problems, fmt.Sprintf("seed pricing change %s has no products or categories", name))
+		}
+		for _, id := range change.Products {
+			if !products[id] {
+				problems = append(problems, fmt.Sprintf("seed pricing change %s product %s is not a seed product", name, id))
+			}
+		}
+		for _, category := range change.Categories {
+			if !categories[category] {
+				problems = append(problems, fmt.Sprintf("seed pricing change %s category %q has no seed products", name, category))
+			}
+		}
+		for _, date := range []string{change.From, change.To} {
+			if _, err := time.Parse(trafficDate, date); date != "" && err != nil {
+				problems = append(problems, fmt.Sprintf("seed pricing change %s from/to must be YYYY-MM-DD, got %q", name, date))
+			}
+		}
+		if change.From != "" && change.To != "" && change.To < change.From {
+			problems = append(problems, fmt.Sprintf("seed pricing change %s to %s is before from %s", name, change.To, change.From))
+		}
+	}
+
+	for _, cuts := range []struct {
+		name string
+		cuts types.TPRandomPriceCuts
+	}{{"markdowns", pricing.Markdowns}, {"priceWars", pricing.PriceWars}} {
+		if cuts.cuts.Chance < 0 || cuts.cuts.Chance > 1 {
+			problems = append(problems, fmt.Sprintf("seed pricing %s chance must be between 0 and 1, got %g", cuts.name, cuts.cuts.Chance))
+		}
+		if cuts.cuts.Chance > 0 && (cuts.cuts.Percent <= 0 || cuts.cuts.Percent >= 100) {
+			problems = append(problems, fmt.Sprintf("seed pricing %s percent must be > 0 and < 100, got %g", cuts.name, cuts.cuts.Percent))
+		}
+		if cuts.cuts.Days < 0 {
+			problems = append(problems, fmt.Sprintf("seed pricing %s days must be >= 0, got %d", cuts.name, cuts.cuts.Days))
+		}
+		if cuts.cuts.Minutes < 0 {
+			problems = append(problems, fmt.Sprintf("seed pricing %s minutes must be >= 0, got %d", cuts.name, cuts.cuts.Minutes))
+		}
+		for _, category := range cuts.cuts.Categories {
+			if !categories[category] {
+				problems = append(problems, fmt.Sprintf("seed pricing %s category %q has no seed products", cuts.name, category))
+			}
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,193 @@
+/*****************************************************************************
+*
+*	File			: profiles.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Store profiles, so one run sells like a mix of store formats, ie convenience stores of small
+*					: baskets next to hypermarkets of big ones, all generated together. A profile (seed Profiles)
+*					: applies to its stores:
+*
+*					:	items		- items per basket, min..max, instead of the terminal type's basket size
+*					:	quantity	- quantity per item, min..max, instead of 1..Max_quantity
+*					:	priceTier	- times the product prices, ie 1.15 for a premium store, 0.9 for a discounter
+*					:	weight		- times the stores' share of the traffic
+*
+*					: The ranges are drawn uniform, normal (around the middle) or skewed (to the min). Stores in
+*					: no profile sell as before, as per the terminal types and the app settings.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"cmd/types"
+
+	"github.com/brianvoe/gofakeit"
+)
+
+const (
+	rangeUniform = "uniform"
+	rangeNormal  = "normal"
+	rangeSkewed  = "skewed"
+)
+
+// Store id => its profile
+type tpProfiles map[string]*types.TPStoreProfile
+
+func newProfiles(seed types.TPSeed) tpProfiles {
+
+	profiles := make(tpProfiles)
+	for i := range seed.Profiles {
+		for _, store := range seed.Profiles[i].Stores {
+			profiles[store] = &seed.Profiles[i]
+		}
+	}
+	return profiles
+}
+
+// The store's traffic weight multiplier.
+func (p tpProfiles) weight(store types.TPStoreStruct) float64 {
+
+	if profile, ok := p[store.Id]; ok && profile.Weight > 0 {
+		return profile.Weight
+	}
+	return 1
+}
+
+// Items in the store's basket, the terminal type's min..max if its profile has none.
+func (p tpProfiles) items(store types.TPStoreStruct, min, max int) int {
+
+	if profile, ok := p[store.Id]; ok && profile.Items.Max > 0 {
+		return drawRange(profile.Items)
+	}
+	return gofakeit.Number(min, max)
+}
+
+// Quantity of a basket item, 1..Max_quantity if the store's profile has none.
+func (p tpProfiles) quantity(store types.TPStoreStruct) int {
+
+	if profile, ok := p[store.Id]; ok && profile.Quantity.Max > 0 {
+		return drawRange(profile.Quantity)
+	}
+	return gofakeit.Number(1, vGeneral.Max_quantity)
+}
+
+// The store's price tier and the profile it is of, 1 and blank if none.
+func (p tpProfiles) tier(store types.TPStoreStruct) (float64, string) {
+
+	if profile, ok := p[store.Id]; ok && profile.PriceTier > 0 && profile.PriceTier != 1 {
+		return profile.PriceTier, profile.Name
+	}
+	return 1, ""
+}
+
+// The product price at the store's price tier.
+func (p tpProfiles) price(store types.TPStoreStruct, price float64) float64 {
+
+	if tier, _ := p.tier(store); tier != 1 {
+		return toFixed(price*tier, 2)
+	}
+	return price
+}
+
+// A number in min..max, as per the distribution.
+func drawRange(r types.TPRange) int {
+
+	min := r.Min
+	if min < 1 {
+		min = 1
+	}
+	spread := float64(r.Max - min)
+
+	var n int
+	switch r.Distribution {
+	case rangeNormal:
+		// 99.7% inside the range, the rest clamped to it
+		n = int(math.Round(float64(min) + spread/2 + rand.NormFloat64()*spread/6))
+	case rangeSkewed:
+		n = min + int(math.Floor((spread+1)*math.Pow(rand.Float64(), 2)))
+	default:
+		n = gofakeit.Number(min, r.Max)
+	}
+
+	if n < min {
+		return min
+	}
+	if n > r.Max {
+		return r.Max
+	}
+	return n
+}
+
+// Each profile is named, of seed stores, a store in one profile at most, and its ranges and multipliers make sense.
+func validateProfiles(seed types.TPSeed) []string {
+
+	var problems []string
+
+	stores := make(map[string]bool)
+	for _, s := range seed.Stores {
+		stores[s.Id] = true
+	}
+
+	names := make(map[string]bool)
+	profileOf := make(map[string]string)
+	for i, profile := range seed.Profiles {
+		name := profile.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+			problems = append(problems, fmt.Sprintf("seed profile %s needs a name", name))
+
+		} else if names[name] {
+			problems = append(problems, fmt.Sprintf("seed profile %s is named more than once", name))
+
+		}
+		names[name] = true
+
+		if len(profile.Stores) == 0 {
+			problems = append(problems, fmt.Sprintf("seed profile %s has no stores", name))
+		}
+		for _, store := range profile.Stores {
+			if !stores[store] {
+				problems = append(problems, fmt.Sprintf("seed profile %s store %s is not a seed store", name, store))
+
+			} else if other, ok := profileOf[store]; ok {
+				problems = append(problems, fmt.Sprintf("seed profile %s store %s is in profile %s already", name, store, other))
+
+			}
+			profileOf[store] = name
+		}
+
+		for _, r := range []struct {
+			name string
+			r    types.TPRange
+		}{
+			{"items", profile.Items},
+			{"quantity", profile.Quantity},
+		} {
+			if r.r.Min < 0 || r.r.Max < 0 || (r.r.Min > 0 && r.r.Max == 0) || (r.r.Max > 0 && r.r.Max < r.r.Min) {
+				problems = append(problems, fmt.Sprintf("seed profile %s %s must be a range 1 <= min <= max, got %d..%d", name, r.name, r.r.Min, r.r.Max))
+			}
+			switch r.r.Distribution {
+			case "", rangeUniform, rangeNormal, rangeSkewed:
+			default:
+				problems = append(problems, fmt.Sprintf("seed profile %s %s distribution must be %s, %s or %s, got %q", name, r.name, rangeUniform, rangeNormal, rangeSkewed, r.r.Distribution))
+			}
+		}
+
+		if profile.PriceTier < 0 {
+			problems = append(problems, fmt.Sprintf("seed profile %s priceTier must be > 0 (0 => 1), got %g", name, profile.PriceTier))
+		}
+		if profile.Weight < 0 {
+			problems = append(problems, fmt.Sprintf("seed profile %s weight must be > 0 (0 => 1), got %g", name, profile.Weight))
+		}
+	}
+
+	return problems
+}
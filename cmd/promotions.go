@@ -0,0 +1,272 @@
+/*****************************************************************************
+*
+*	File			: promotions.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Promotions, from the seed "promotions", applied as the basket is built:
+*
+*					:	percent		- {"id": "P1", "type": "percent", "products": ["000000001"], "percent": 10}
+*					:	buy_x_get_y	- {"id": "P2", "type": "buy_x_get_y", "products": ["000000014"], "buy": 2, "get": 1}
+*					:	bundle		- {"id": "P3", "type": "bundle", "products": ["000000004", "000000012"], "price": 99.99}
+*					:	category	- {"id": "P4", "type": "category", "categories": ["Cleaning"], "percent": 15}
+*
+*					: Any of them can be time limited, "from"/"to" dates (YYYY-MM-DD) and "hours" (as store hours), and
+*					: limited to "stores", all in the store's local time. Promotions don't stack, a line gets the best of
+*					: its percent, buy_x_get_y and category promotions, bundles then go to the lines without one.
+*
+*					: The line discount is on the basket item, with its promotionId, the basket discount is their sum
+*					: and nett is price * quantity less the discounts, so the discounts reconcile to the totals.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"cmd/types"
+)
+
+const (
+	promoPercent   = "percent"
+	promoBuyXGetY  = "buy_x_get_y"
+	promoBundle    = "bundle"
+	promoCategory  = "category"
+	promotionsDate = trafficDate
+)
+
+type tpPromotions struct {
+	promotions []types.TPPromotion
+}
+
+func newPromotions(seed types.TPSeed) *tpPromotions {
+	return &tpPromotions{promotions: seed.Promotions}
+}
+
+func contains(list []string, s string) bool {
+
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Is the promotion on in the store at t, t being store local time
+func promotionActive(promo types.TPPromotion, store types.TPStoreStruct, t time.Time) bool {
+
+	if len(promo.Stores) > 0 && !contains(promo.Stores, store.Id) {
+		return false
+	}
+
+	day := t.Format(promotionsDate)
+	if (promo.From != "" && day < promo.From) || (promo.To != "" && day > promo.To) {
+		return false
+	}
+
+	if len(promo.Hours) == 0 {
+		return true
+	}
+	for _, hours := range promo.Hours {
+		if onShift(hours, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// The discount of a percent, buy_x_get_y or category promotion on the line, 0 => does not apply
+func lineDiscount(promo types.TPPromotion, item *types.BasketItem) float64 {
+
+	gross := item.Price * float64(item.Quantity)
+
+	switch promo.Type {
+	case promoPercent:
+		if contains(promo.Products, item.Id) {
+			return gross * promo.Percent / 100
+		}
+
+	case promoCategory:
+		if contains(promo.Categories, item.Category) {
+			return gross * promo.Percent / 100
+		}
+
+	case promoBuyXGetY:
+		if contains(promo.Products, item.Id) {
+			free := int(item.Quantity) / (promo.Buy + promo.Get) * promo.Get
+			return item.Price * float64(free)
+		}
+	}
+	return 0
+}
+
+// Apply the active promotions to the basket items, at t in the store, returns the basket discount.
+func (p *tpPromotions) apply(items []*types.BasketItem, store types.TPStoreStruct, t time.Time) float64 {
+
+	var active []types.TPPromotion
+	for _, promo := range p.promotions {
+		if promotionActive(promo, store, t) {
+			active = append(active, promo)
+		}
+	}
+	if len(active) == 0 {
+		return 0
+	}
+
+	// The best line promotion per line
+	for _, item := range items {
+		for _, promo := range active {
+			if discount := toFixed(lineDiscount(promo, item), 2); discount > item.Discount {
+				item.Discount = discount
+				item.PromotionId = promo.Id
+			}
+		}
+	}
+
+	// Bundles, as many as the lines without a promotion make up
+	for _, promo := range active {
+		if promo.Type == promoBundle {
+			applyBundle(promo, items, varCurr.price(promo.Price, storeCurrency(store)))
+		}
+	}
+
+	total := 0.0
+	for _, item := range items {
+		total += item.Discount
+	}
+	return toFixed(total, 2)
+}
+
+// A bundle is one of each of its products for the bundle price, in the basket's currency, the discount is spread over
+// the products by price, and a product's over its lines that make up the bundles, by the quantity each adds.
+func applyBundle(promo types.TPPromotion, items []*types.BasketItem, price float64) {
+
+	lines := make(map[string][]*types.BasketItem) // undiscounted lines per bundle product
+	quantity := make(map[string]int)
+	for _, item := range items {
+		if item.PromotionId != "" || !contains(promo.Products, item.Id) {
+			continue
+		}
+		lines[item.Id] = append(lines[item.Id], item)
+		quantity[item.Id] += int(item.Quantity)
+	}
+
+	bundles := -1
+	full := 0.0
+	for _, id := range promo.Products {
+		if bundles == -1 || quantity[id] < bundles {
+			bundles = quantity[id]
+		}
+		if len(lines[id]) > 0 {
+			full += lines[id][0].Price
+		}
+	}
+	if bundles <= 0 || full <= price {
+		return
+	}
+
+	saving := full - price
+	for _, id := range promo.Products {
+		// Per bundle, less than the product's price, so no line is discounted below 0
+		unit := saving * lines[id][0].Price / full
+		left := bundles
+		for _, item := range lines[id] {
+			if left == 0 {
+				break
+			}
+			units := int(item.Quantity)
+			if units > left {
+				units = left
+			}
+			item.Discount = toFixed(float64(units)*unit, 2)
+			item.PromotionId = promo.Id
+			left -= units
+		}
+	}
+}
+
+// Every promotion has a unique id, a known type with its values, and refers to seed products, categories and stores.
+func validatePromotions(seed types.TPSeed) []string {
+
+	var problems []string
+	add := func(promo types.TPPromotion, format string, a ...interface{}) {
+		problems = append(problems, fmt.Sprintf("seed promotion %s ", promo.Id)+fmt.Sprintf(format, a...))
+	}
+
+	products := make(map[string]bool)
+	categories := make(map[string]bool)
+	for _, product := range seed.Products {
+		products[product.Id] = true
+		categories[product.Category] = true
+	}
+	stores := make(map[string]bool)
+	for _, store := range seed.Stores {
+		stores[store.Id] = true
+	}
+
+	ids := make(map[string]bool)
+	for _, promo := range seed.Promotions {
+		if promo.Id == "" {
+			problems = append(problems, fmt.Sprintf("seed promotion %q has no id", promo.Name))
+		} else if ids[promo.Id] {
+			add(promo, "is duplicated")
+		}
+		ids[promo.Id] = true
+
+		switch promo.Type {
+		case promoPercent, promoCategory:
+			if promo.Percent <= 0 || promo.Percent > 100 {
+				add(promo, "percent must be > 0 and <= 100, got %g", promo.Percent)
+			}
+		case promoBuyXGetY:
+			if promo.Buy < 1 || promo.Get < 1 {
+				add(promo, "buy and get must be >= 1, got %d and %d", promo.Buy, promo.Get)
+			}
+		case promoBundle:
+			if len(promo.Products) < 2 || promo.Price <= 0 {
+				add(promo, "bundle needs 2 or more products and a price > 0")
+			}
+		default:
+			add(promo, "type must be %s, %s, %s or %s, got %q", promoPercent, promoBuyXGetY, promoBundle, promoCategory, promo.Type)
+		}
+
+		if promo.Type == promoCategory {
+			if len(promo.Categories) == 0 {
+				add(promo, "has no categories")
+			}
+		} else if len(promo.Products) == 0 {
+			add(promo, "has no products")
+		}
+
+		for _, id := range promo.Products {
+			if !products[id] {
+				add(promo, "product %s is not a seed product", id)
+			}
+		}
+		for _, category := range promo.Categories {
+			if !categories[category] {
+				add(promo, "category %q has no seed products", category)
+			}
+		}
+		for _, id := range promo.Stores {
+			if !stores[id] {
+				add(promo, "store %s is not a seed store", id)
+			}
+		}
+		for _, date := range []string{promo.From, promo.To} {
+			if _, err := time.Parse(promotionsDate, date); date != "" && err != nil {
+				add(promo, "from/to must be YYYY-MM-DD, got %q", date)
+			}
+		}
+		for _, hours := range promo.Hours {
+			problems = append(problems, validateShift(fmt.Sprintf("seed promotion %s hours", promo.Id), hours)...)
+		}
+	}
+
+	return problems
+}
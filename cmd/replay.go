@@ -0,0 +1,264 @@
+/*****************************************************************************
+*
+*	File			: replay.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Replay a run saved with Json_to_file = 1, re-posting its baskets and payments into the sinks
+*					: enabled for env, ie produce once to file and then load the identical data into Kafka or Mongo.
+*					: The run's inventory movements, price changes, online order events and anomaly labels, those it
+*					: saved, are replayed too, each in between the sales as per its saved produceTimestamp.
+*
+*					: go run ./cmd replay --env <env> --run-id <runId> [--rate 50] [--dry-run]
+*
+*					: The replay is a run of its own, a new runId, manifest and json_save files (if enabled), the documents
+*					: are stamped with a new produceTimestamp, everything else is as saved. The saved documents are as
+*					: posted, faults and all, see faults.go, those are replayed as they are, no faults are injected on
+*					: top. Those that no longer decode, ie a wrong_type, or lost their invoiceNumber are skipped, and
+*					: counted.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+
+	"cmd/types"
+)
+
+// A saved document to replay, at its saved produceTimestamp.
+type tpReplayStep struct {
+	at   int64
+	sale bool
+	post func(sinks *tpSinks)
+}
+
+// The json_save files are the pretty printed documents each followed by ",\n", close enough to a JSON array. The
+// documents are decoded one by one, those that do not decode, saved with a fault, are skipped, and counted.
+func loadSaved[T any](fileName string) ([]T, int, error) {
+
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data = bytes.TrimSuffix(bytes.TrimSpace(data), []byte(","))
+	data = append(append([]byte("["), data...), ']')
+
+	var raws []json.RawMessage
+	if err = json.Unmarshal(data, &raws); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", fileName, err)
+	}
+
+	docs := make([]T, 0, len(raws))
+	skipped := 0
+	for i, raw := range raws {
+		var doc T
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			skipped++
+			if vGeneral.Debuglevel >= 1 {
+				grpcLog.Warningln(fmt.Sprintf("%s document %d skipped: %s", fileName, i+1, err))
+
+			}
+			continue
+
+		}
+		docs = append(docs, doc)
+	}
+	return docs, skipped, nil
+}
+
+// The run's saved documents of the kind, if it saved any, to replay at their produceTimestamp with post.
+func loadSavedSteps[T proto.Message](runId string, kind string, post func(sinks *tpSinks, docs []T)) ([]tpReplayStep, error) {
+
+	fileName := fmt.Sprintf("%s%s%s_%s.json", vGeneral.Output_path, pathSep, runId, kind)
+	docs, skipped, err := loadSaved[T](fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+
+	} else if err != nil {
+		return nil, err
+
+	}
+	if skipped > 0 {
+		grpcLog.Warningln(fmt.Sprintf("%s documents that do not decode, skipped : %d", kind, skipped))
+
+	}
+
+	steps := make([]tpReplayStep, 0, len(docs))
+	for _, doc := range docs {
+		doc := doc
+		m := doc.ProtoReflect()
+		steps = append(steps, tpReplayStep{
+			at:   m.Get(m.Descriptor().Fields().ByName("produceTimestamp")).Int(),
+			post: func(sinks *tpSinks) { post(sinks, []T{doc}) },
+		})
+	}
+	return steps, nil
+}
+
+func runReplay(opts *tpOptions) {
+
+	loadSettings(opts, false, false)
+
+	if opts.runId == "" {
+		grpcLog.Fatalln("replay: --run-id is required")
+
+	}
+
+	// The saved documents are as posted, faults and all, they are replayed as they are, no faults injected on top
+	varFault = nil
+
+	basketFile := fmt.Sprintf("%s%s%s_%s.json", vGeneral.Output_path, pathSep, opts.runId, "basket")
+	baskets, skippedBaskets, err := loadSaved[*types.Pb_Basket](basketFile)
+	if err != nil {
+		grpcLog.Fatalln("Error Reading Basket File: ", err)
+
+	}
+
+	pmntFile := fmt.Sprintf("%s%s%s_%s.json", vGeneral.Output_path, pathSep, opts.runId, "pmnt")
+	payments, skippedPayments, err := loadSaved[*types.Pb_Payment](pmntFile)
+	if err != nil {
+		grpcLog.Fatalln("Error Reading Payment File: ", err)
+
+	}
+
+	// Payments are matched to their basket by invoiceNumber, a missing_field fault may have left either without one
+	byInvoice := make(map[string]*types.Pb_Payment)
+	for _, pb_Payment := range payments {
+		if pb_Payment.InvoiceNumber == "" {
+			skippedPayments++
+			continue
+
+		}
+		byInvoice[pb_Payment.InvoiceNumber] = pb_Payment
+	}
+
+	// The sales, and the other documents the run saved, in the order they were produced
+	var steps []tpReplayStep
+	for _, pb_Basket := range baskets {
+
+		pb_Basket := pb_Basket
+		if pb_Basket.InvoiceNumber == "" {
+			skippedBaskets++
+			continue
+
+		}
+
+		pb_Payment, ok := byInvoice[pb_Basket.InvoiceNumber]
+		if !ok {
+			grpcLog.Warningln("No payment for invoice, skipped :", pb_Basket.InvoiceNumber)
+			continue
+
+		}
+
+		steps = append(steps, tpReplayStep{
+			at:   pb_Basket.ProduceTimestamp,
+			sale: true,
+			post: func(sinks *tpSinks) { sinks.post(pb_Basket, pb_Payment) },
+		})
+	}
+
+	saved := make(map[string]int)
+	for _, load := range []struct {
+		kind  string
+		steps func(runId string, kind string) ([]tpReplayStep, error)
+	}{
+		{"inventory", func(runId, kind string) ([]tpReplayStep, error) {
+			return loadSavedSteps(runId, kind, (*tpSinks).postMovements)
+		}},
+		{"price", func(runId, kind string) ([]tpReplayStep, error) {
+			return loadSavedSteps(runId, kind, (*tpSinks).postPriceChanges)
+		}},
+		{"orders", func(runId, kind string) ([]tpReplayStep, error) {
+			return loadSavedSteps(runId, kind, (*tpSinks).postOrderEvents)
+		}},
+		{"labels", func(runId, kind string) ([]tpReplayStep, error) {
+			return loadSavedSteps(runId, kind, (*tpSinks).postLabels)
+		}},
+	} {
+		kindSteps, err := load.steps(opts.runId, load.kind)
+		if err != nil {
+			grpcLog.Fatalln(fmt.Sprintf("Error Reading %s File: ", load.kind), err)
+
+		}
+		saved[load.kind] = len(kindSteps)
+		steps = append(steps, kindSteps...)
+	}
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].at < steps[j].at })
+
+	runId = uuid.New().String()
+
+	grpcLog.Infoln("*")
+	grpcLog.Infoln("* Replay Run Id               :", opts.runId)
+	grpcLog.Infoln("* Baskets                     :", len(baskets))
+	grpcLog.Infoln("* Payments                    :", len(payments))
+	grpcLog.Infoln("* Baskets Skipped             :", skippedBaskets)
+	grpcLog.Infoln("* Payments Skipped            :", skippedPayments)
+	grpcLog.Infoln("* Inventory Movements         :", saved["inventory"])
+	grpcLog.Infoln("* Price Changes               :", saved["price"])
+	grpcLog.Infoln("* Order Events                :", saved["orders"])
+	grpcLog.Infoln("* Anomaly Labels              :", saved["labels"])
+	grpcLog.Infoln("* Run Id                      :", runId)
+	grpcLog.Infoln("*")
+
+	sinks := openSinks(opts.dryRun)
+
+	// The stock, prices, orders and anomalies are not enabled by a replay, their json_save files are opened as per
+	// what the run saved
+	if vGeneral.Json_to_file == 1 && !opts.dryRun {
+		for kind, f := range map[string]**os.File{"inventory": &sinks.f_inventory, "price": &sinks.f_price, "orders": &sinks.f_orders, "labels": &sinks.f_labels} {
+			if *f == nil && saved[kind] > 0 {
+				*f = openOutputFile(kind)
+			}
+		}
+	}
+
+	pacer := newPacer(vGeneral.Rate)
+
+	var vStart = time.Now()
+	replayed := 0
+	others := 0
+	for _, step := range steps {
+
+		step.post(sinks)
+		if !step.sale {
+			others++
+			continue
+
+		}
+		replayed++
+
+		pacer.wait(1)
+
+	}
+
+	// Flush the Kafka queue and trailing Mongo batch, before we report.
+	sinks.close()
+
+	vElapse := time.Since(vStart)
+
+	grpcLog.Infoln("")
+	grpcLog.Infoln("**** DONE Replaying ****")
+	grpcLog.Infoln("")
+	grpcLog.Infoln("Elapsed Time (Seconds)        : ", vElapse.Seconds())
+	grpcLog.Infoln("Run Id                        : ", runId)
+	grpcLog.Infoln("Records Replayed              : ", replayed)
+	grpcLog.Infoln("Other Documents Replayed      : ", others)
+	grpcLog.Infoln(fmt.Sprintf("                              :  %.3f Txns/Second", float64(replayed)/vElapse.Seconds()))
+
+	grpcLog.Infoln("")
+
+}
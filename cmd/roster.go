@@ -0,0 +1,207 @@
+/*****************************************************************************
+*
+*	File			: roster.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Clerks work at their seed storeId, and, if they have shifts, only during those. A basket's clerk is
+*					: picked from the store's clerks on shift at the time of the sale, as per their weights.
+*
+*					: {"id": "10001", "name": "Martin", "storeId": "324213412",
+*					:  "shifts": [{"days": ["mon", "tue", "wed", "thu", "fri"], "start": "06:00", "end": "14:00"}]}
+*
+*					: A shift with no days is every day, an end before the start runs past midnight. A clerk without
+*					: shifts is always on. Stores are only picked while at least one of their clerks is on shift.
+*					: Shifts are in the store's local time, see timezones.go.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"cmd/types"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Minutes since midnight of a HH:MM
+func shiftMinutes(hhmm string) (int, error) {
+
+	t, err := time.Parse("15:04", hhmm)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func onDay(days []string, day time.Weekday) bool {
+
+	if len(days) == 0 {
+		return true
+	}
+	for _, d := range days {
+		if weekdays[strings.ToLower(d)] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Is the shift on at t, shifts that end before they start run past midnight into the next day.
+func onShift(shift types.TPShiftStruct, t time.Time) bool {
+
+	start, _ := shiftMinutes(shift.Start)
+	end, _ := shiftMinutes(shift.End)
+	now := t.Hour()*60 + t.Minute()
+
+	if start <= end {
+		return onDay(shift.Days, t.Weekday()) && now >= start && now < end
+	}
+
+	// Overnight, either the evening of a shift day, or the morning after one
+	return (onDay(shift.Days, t.Weekday()) && now >= start) ||
+		(onDay(shift.Days, t.Add(-24*time.Hour).Weekday()) && now < end)
+}
+
+func clerkOnShift(clerk types.TPClerkStruct, t time.Time) bool {
+
+	if len(clerk.Shifts) == 0 {
+		return true
+	}
+	for _, shift := range clerk.Shifts {
+		if onShift(shift, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// The store's clerks on shift at t, store local time, their positions in the seed and their weights.
+func clerksOnShift(seed types.TPSeed, store types.TPStoreStruct, t time.Time) (positions []int, weights []float64) {
+
+	t = storeTime(store, t)
+	for _, i := range varPick.clerks[store.Id] {
+		if clerk := seed.Clerks[i]; clerkOnShift(clerk, t) {
+			positions = append(positions, i)
+			weights = append(weights, clerk.Weight)
+		}
+	}
+	return positions, seedWeights(weights, vGeneral.Distribution)
+}
+
+// Pick the store, as per the Distribution, out of those open with a clerk on shift at t, and a clerk on shift in it.
+// If the roster leaves nobody on shift anywhere we fall back to ignoring the shifts.
+func pickStoreClerk(seed types.TPSeed, t time.Time) (store int, clerk int) {
+
+	store = vGeneral.Store
+	if vGeneral.Store == 0 {
+		store = varPick.stores.pick()
+	}
+
+	positions, weights := clerksOnShift(seed, seed.Stores[store], t)
+	if (len(positions) == 0 || !storeOpen(seed.Stores[store], t)) && vGeneral.Store == 0 {
+		// Closed or nobody on shift in the store we picked, pick again from the stores that are open.
+		var open []int
+		var openW []float64
+		for i := range seed.Stores {
+			if p, _ := clerksOnShift(seed, seed.Stores[i], t); len(p) > 0 && storeOpen(seed.Stores[i], t) {
+				open = append(open, i)
+				openW = append(openW, varPick.stores.share(i))
+			}
+		}
+		if len(open) > 0 {
+			store = open[newPicker(openW).pick()]
+			positions, weights = clerksOnShift(seed, seed.Stores[store], t)
+		}
+	}
+
+	if len(positions) == 0 {
+		if vGeneral.Debuglevel > 1 {
+			grpcLog.Warningln("No clerk on shift at", seed.Stores[store].Name, t.Format("Mon 15:04"), ", ignoring the shifts")
+
+		}
+		for i, c := range seed.Clerks {
+			if c.StoreId == seed.Stores[store].Id {
+				positions = append(positions, i)
+				weights = append(weights, c.Weight)
+			}
+		}
+		weights = seedWeights(weights, vGeneral.Distribution)
+	}
+
+	return store, positions[newPicker(weights).pick()]
+}
+
+// A shift, or store opening hours, what being the name to report it under.
+func validateShift(what string, shift types.TPShiftStruct) []string {
+
+	var problems []string
+
+	if _, err := shiftMinutes(shift.Start); err != nil {
+		problems = append(problems, fmt.Sprintf("%s start must be HH:MM, got %q", what, shift.Start))
+	}
+	if _, err := shiftMinutes(shift.End); err != nil {
+		problems = append(problems, fmt.Sprintf("%s end must be HH:MM, got %q", what, shift.End))
+	}
+	if shift.Start == shift.End {
+		problems = append(problems, fmt.Sprintf("%s %s-%s is empty", what, shift.Start, shift.End))
+	}
+	for _, d := range shift.Days {
+		if _, ok := weekdays[strings.ToLower(d)]; !ok {
+			problems = append(problems, fmt.Sprintf("%s day must be sun, mon, tue, wed, thu, fri or sat, got %q", what, d))
+		}
+	}
+
+	return problems
+}
+
+// Every clerk belongs to a store in the seed, every store has clerks and the shifts make sense.
+func validateRoster(seed types.TPSeed) []string {
+
+	var problems []string
+
+	stores := make(map[string]bool)
+	for _, store := range seed.Stores {
+		stores[store.Id] = true
+	}
+
+	staffed := make(map[string]int)
+	for _, clerk := range seed.Clerks {
+		switch {
+		case clerk.StoreId == "":
+			problems = append(problems, fmt.Sprintf("seed clerk %s (%s) has no storeId", clerk.Id, clerk.Name))
+
+		case !stores[clerk.StoreId]:
+			problems = append(problems, fmt.Sprintf("seed clerk %s (%s) storeId %s is not a seed store", clerk.Id, clerk.Name, clerk.StoreId))
+
+		default:
+			staffed[clerk.StoreId]++
+		}
+
+		for _, shift := range clerk.Shifts {
+			problems = append(problems, validateShift(fmt.Sprintf("seed clerk %s shift", clerk.Id), shift)...)
+		}
+	}
+
+	for _, store := range seed.Stores {
+		if staffed[store.Id] == 0 {
+			problems = append(problems, fmt.Sprintf("seed store %s (%s) has no clerks", store.Id, store.Name))
+		}
+	}
+
+	return problems
+}
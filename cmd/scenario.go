@@ -0,0 +1,482 @@
+/*****************************************************************************
+*
+*	File			: scenario.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Multi-phase runs, a scenario file (YAML, TOML or JSON) of phases, each with its own settings and sinks,
+*					: one after the other or overlapping:
+*
+*					: go run ./cmd scenario --env <env> --file example/scenario.json [--dry-run]
+*
+*					: {"name": "spike test",
+*					:  "phases": [
+*					:    {"name": "warmup", "duration": "5m", "rate": 50},
+*					:    {"name": "spike",  "duration": "10m", "rate": 2000, "set": {"app.Late_payments": 0.2}},
+*					:    {"name": "steady", "rate": 200, "sinks": ["kafka", "mongo"]},
+*					:    {"name": "big baskets", "start": "2m", "count": 5000, "rate": 20,
+*					:     "set": {"app.Store": 3, "app.Max_items_basket": 40, "app.Max_quantity": 12}}]}
+*
+*					: A phase starts start after the scenario did, or, without one, once the phase before it is done, and
+*					: runs for its duration and/or count sales, whichever comes first, neither => until Ctrl-C. rate, sinks
+*					: (kafka, mongo, file) and set, <section>.<key> overrides as per --set, are its own settings, on top of
+*					: the env's configuration. Each phase is a run of its own, <runId>_<phase>, its own manifest and
+*					: json_save files, to verify by. The seed, stock, prices, customers and online orders are the
+*					: scenario's, shared by the phases, as per the env's configuration, a phase cannot set the
+*					: scenarioSettings they are built by.
+*
+*					: The phases run in the one loop, the next sale due of whichever phase is next, so overlapping
+*					: phases interleave at their own rates. Ctrl-C ends the scenario, the phases running are done as they
+*					: are, and every phase's sinks flushed and closed.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"cmd/types"
+)
+
+const (
+	sinkKafka = "kafka"
+	sinkMongo = "mongo"
+	sinkFile  = "file"
+)
+
+// The settings the seed, customers and prices are built by, once, for all the phases, a phase cannot set them
+var scenarioSettings = []string{"app.SeedFile", "app.Seed_source", "app.Seed_location", "app.Seed_prefix", "app.Seed_refresh", "app.Customers", "app.Currency", "app.Latency"}
+
+// A phase, its configuration and where it is at
+type tpPhase struct {
+	phase     types.TPScenarioPhase
+	app       types.Tp_general
+	kafka     types.TKafka
+	mongo     types.TMongodb
+	faults    *tpFaults
+	terminals *tpTerminals // as per its Max_items_basket and Terminals
+	pickers   tpPickers    // as per its Distribution and Skew
+	traffic   *tpTraffic   // its product pickers, with the category boosts
+	runId     string
+	sinks     *tpSinks
+	start     time.Time // zero => once the phase before it is done
+	end       time.Time // zero => until its count
+	next      time.Time // when its next sale is due
+	vStart    time.Time
+	count     int
+	walkouts  int
+	started   bool
+	done      bool
+}
+
+func readScenario(fileName string) (types.TPScenario, error) {
+
+	var scenario types.TPScenario
+	if err := readConfigFile(fileName, &scenario); err != nil {
+		return scenario, fmt.Errorf("error reading scenario file %s: %w", fileName, err)
+	}
+	return scenario, nil
+}
+
+// The phase's --set overrides, its rate, count, sinks and set.
+func phaseSettings(phase types.TPScenarioPhase) []string {
+
+	var settings []string
+	if phase.Rate > 0 {
+		settings = append(settings, fmt.Sprintf("app.rate=%g", phase.Rate))
+	}
+	settings = append(settings, fmt.Sprintf("app.testsize=%d", phase.Count))
+
+	if len(phase.Sinks) > 0 {
+		enabled := func(sink string) int {
+			if contains(phase.Sinks, sink) {
+				return 1
+			}
+			return 0
+		}
+		settings = append(settings,
+			fmt.Sprintf("app.KafkaEnabled=%d", enabled(sinkKafka)),
+			fmt.Sprintf("app.MongoAtlasEnabled=%d", enabled(sinkMongo)),
+			fmt.Sprintf("app.Json_to_file=%d", enabled(sinkFile)))
+	}
+
+	for key, value := range phase.Set {
+		settings = append(settings, fmt.Sprintf("%s=%v", key, value))
+	}
+	return settings
+}
+
+// The phase's name, as it goes into its runId
+func phaseId(name string) string {
+
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return '-'
+	}, name)
+}
+
+// Make the phase's configuration, faults and run the current ones, the sale code reads them from there.
+func (p *tpPhase) use() {
+
+	vGeneral = p.app
+	vKafka = p.kafka
+	vMongodb = p.mongo
+	varFault = p.faults
+	varTerm = p.terminals
+	varPick = p.pickers
+	varTraff = p.traffic
+	runId = p.runId
+}
+
+func (p *tpPhase) begin(now time.Time, dryRun bool) {
+
+	p.use()
+	p.started = true
+	p.vStart = now
+	p.next = now
+	if p.start.IsZero() {
+		p.start = now
+	}
+	if d, _ := time.ParseDuration(p.phase.Duration); d > 0 {
+		p.end = p.start.Add(d)
+	}
+	p.sinks = openSinks(dryRun)
+
+	grpcLog.Infoln("")
+	grpcLog.Infoln("**** Phase Started           :", p.phase.Name)
+	grpcLog.Infoln("* Run Id                      :", p.runId)
+	grpcLog.Infoln("* Rate                        :", p.app.Rate)
+	if !p.end.IsZero() {
+		grpcLog.Infoln("* Until                       :", p.end.Format(time.RFC3339))
+	}
+	if p.app.Testsize > 0 {
+		grpcLog.Infoln("* Count                       :", p.app.Testsize)
+	}
+	grpcLog.Infoln("")
+}
+
+// The phase is done, its sinks stay open for the order events still to come, they are closed as the scenario ends.
+func (p *tpPhase) finish(now time.Time) {
+
+	p.use()
+	p.done = true
+
+	vElapse := now.Sub(p.vStart)
+	grpcLog.Infoln("")
+	grpcLog.Infoln("**** Phase Done              :", p.phase.Name)
+	grpcLog.Infoln("Run Id                        : ", p.runId)
+	grpcLog.Infoln("Elapsed Time (Seconds)        : ", vElapse.Seconds())
+	grpcLog.Infoln("Records Processed             : ", p.count)
+	if p.walkouts > 0 {
+		grpcLog.Infoln("Walk outs (out of stock)      : ", p.walkouts)
+	}
+	varFault.report()
+	grpcLog.Infoln(fmt.Sprintf("                              :  %.3f Txns/Second", float64(p.count)/vElapse.Seconds()))
+	grpcLog.Infoln("")
+}
+
+// When the phase's next sale is due, after one at now, as per its Rate, or Sleep, scaled by the demand.
+func (p *tpPhase) pace(now time.Time, demand float64) {
+
+	if p.app.Rate > 0 {
+		p.next = p.next.Add(time.Duration(float64(time.Second) / (p.app.Rate * demand)))
+
+		// Don't burst to catch up after a stall, as tpPacer
+		if now.Sub(p.next) > time.Second {
+			p.next = now
+		}
+		return
+
+	}
+
+	p.next = now
+	if p.app.Sleep > 0 {
+		p.next = now.Add(time.Duration(float64(rand.Intn(p.app.Sleep))/demand) * time.Millisecond)
+	}
+}
+
+// Sleep until t, or Ctrl-C, whichever comes first.
+func sleepUntil(ctx context.Context, t time.Time) {
+
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+func runScenario(opts *tpOptions) {
+
+	if opts.scenario == "" {
+		grpcLog.Fatalln("scenario: --file is required")
+
+	}
+
+	scenario, err := readScenario(opts.scenario)
+	if err != nil {
+		grpcLog.Fatalln(err)
+
+	}
+	if problems := validateScenario(scenario); len(problems) > 0 {
+		fatalProblems(fmt.Sprintf("Scenario %s is invalid:", opts.scenario), problems)
+
+	}
+
+	// The env's configuration, the seed and all that is shared
+	loadSettings(opts, false, false)
+	loadRun()
+	scenarioId := runId
+	scenarioApp, scenarioMongo := vGeneral, vMongodb
+
+	grpcLog.Infoln("* Scenario                    :", scenario.Name)
+
+	// Each phase's own configuration, the env's plus its settings
+	var phases []*tpPhase
+	for i, phase := range scenario.Phases {
+		if phase.Name == "" {
+			phase.Name = fmt.Sprintf("phase%d", i+1)
+		}
+
+		phaseOpts := *opts
+		phaseOpts.settings = append(append([]string{}, opts.settings...), "app.echoconfig=0")
+		phaseOpts.settings = append(phaseOpts.settings, phaseSettings(phase)...)
+		loadSettings(&phaseOpts, false, false)
+
+		if problems := validateSeedConfig(vGeneral, varSeed); len(problems) > 0 {
+			fatalProblems(fmt.Sprintf("Scenario phase %s and the Seed do not match:", phase.Name), problems)
+
+		}
+
+		p := &tpPhase{
+			phase:     phase,
+			app:       vGeneral,
+			kafka:     vKafka,
+			mongo:     vMongodb,
+			faults:    newFaults(vGeneral),
+			terminals: newTerminals(varSeed),
+			pickers:   newPickers(varSeed),
+			runId:     fmt.Sprintf("%s_%s", scenarioId, phaseId(phase.Name)),
+		}
+		varPick = p.pickers
+		p.traffic = newTraffic(varSeed)
+		phases = append(phases, p)
+	}
+
+	refresh := newSeedRefresh()
+
+	// Ctrl-C ends the scenario, rather than the process, so the sinks are flushed and closed
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Latency = 1, as per the env's configuration, watch the documents of all the phases arrive, see latency.go
+	vGeneral, vMongodb = scenarioApp, scenarioMongo
+	latency := startLatency()
+
+	var vStart = time.Now()
+	for _, p := range phases {
+		if d, _ := time.ParseDuration(p.phase.Start); p.phase.Start != "" {
+			p.start = vStart.Add(d)
+		}
+	}
+	count := 0
+	for ctx.Err() == nil {
+		now := time.Now()
+
+		// Start the phases that are due, pick the one whose sale is next, and when the next phase starts
+		var current *tpPhase
+		var wake time.Time
+		for i, p := range phases {
+			if p.done {
+				continue
+			}
+			if !p.started {
+				if p.start.IsZero() && i > 0 && !phases[i-1].done {
+					continue
+				}
+				if p.start.After(now) {
+					if wake.IsZero() || p.start.Before(wake) {
+						wake = p.start
+					}
+					continue
+				}
+				p.begin(now, opts.dryRun)
+			}
+			if current == nil || p.next.Before(current.next) {
+				current = p
+			}
+		}
+
+		if current == nil {
+			if wake.IsZero() {
+				break
+			}
+			sleepUntil(ctx, wake)
+			continue
+		}
+
+		due := current.next
+		if !wake.IsZero() && wake.Before(due) {
+			due = wake
+		}
+		if d := time.Until(due); d > 0 {
+			sleepUntil(ctx, due)
+			if ctx.Err() != nil || due != current.next {
+				continue
+			}
+		}
+
+		now = time.Now()
+		current.use()
+
+		if !current.end.IsZero() && !now.Before(current.end) {
+			current.finish(now)
+			continue
+		}
+
+		// Seed_refresh, pick up the seed's changes, see seedsource.go, the terminals, pickers and traffic are the phases' own
+		if refresh.due() {
+			refreshSeed()
+			for _, p := range phases {
+				p.use()
+				p.terminals = newTerminals(varSeed)
+				p.pickers = newPickers(varSeed)
+				varPick = p.pickers
+				p.traffic = newTraffic(varSeed)
+			}
+			current.use()
+		}
+
+		// Everything is closed, try again in a minute
+		traffic := varTraff.at(now)
+		if traffic.demand <= 0 {
+			current.next = now.Add(time.Minute)
+			continue
+		}
+
+		if !sell(current.sinks, traffic, now, false) {
+			current.walkouts++
+		}
+		current.count++
+		count++
+
+		if current.app.Testsize > 0 && current.count >= current.app.Testsize {
+			current.finish(time.Now())
+			continue
+		}
+		current.pace(now, traffic.demand)
+	}
+
+	// Interrupted, the phases still running are done as they are
+	if ctx.Err() != nil {
+		grpcLog.Infoln("")
+		grpcLog.Infoln("**** Scenario Interrupted ****")
+		for _, p := range phases {
+			if p.started && !p.done {
+				p.finish(time.Now())
+			}
+		}
+	}
+
+	// The online order steps still ahead, into the sinks of the phase that started last, then close them all
+	var last *tpPhase
+	for _, p := range phases {
+		if p.started && (last == nil || !p.vStart.Before(last.vStart)) {
+			last = p
+		}
+	}
+	if last != nil {
+		last.use()
+		last.sinks.postOrderEvents(varOrder.drain())
+	}
+
+	for _, p := range phases {
+		if p.sinks != nil {
+			p.use()
+			p.sinks.close()
+		}
+	}
+
+	vElapse := time.Since(vStart)
+	vGeneral, vMongodb = scenarioApp, scenarioMongo
+	latency.stop()
+
+	grpcLog.Infoln("")
+	grpcLog.Infoln("**** DONE Scenario ****")
+	grpcLog.Infoln("")
+	grpcLog.Infoln("Scenario                      : ", scenario.Name)
+	grpcLog.Infoln("Elapsed Time (Seconds)        : ", vElapse.Seconds())
+	grpcLog.Infoln("Phases                        : ", len(phases))
+	grpcLog.Infoln("Records Processed             : ", count)
+	grpcLog.Infoln(fmt.Sprintf("                              :  %.3f Txns/Second", float64(count)/vElapse.Seconds()))
+	latency.report()
+	grpcLog.Infoln("")
+}
+
+// The phases have a start and duration that parse, sinks we know, and only the last to start may run forever.
+func validateScenario(scenario types.TPScenario) []string {
+
+	var problems []string
+
+	if len(scenario.Phases) == 0 {
+		problems = append(problems, "scenario has no phases")
+	}
+
+	names := make(map[string]bool)
+	for i, phase := range scenario.Phases {
+		name := phase.Name
+		if name == "" {
+			name = fmt.Sprintf("phase%d", i+1)
+		}
+		if names[name] {
+			problems = append(problems, fmt.Sprintf("scenario phase %s is there twice, the names make the runIds", name))
+		}
+		names[name] = true
+
+		for _, value := range []struct {
+			key   string
+			value string
+		}{{"start", phase.Start}, {"duration", phase.Duration}} {
+			if d, err := time.ParseDuration(value.value); value.value != "" && (err != nil || d < 0) {
+				problems = append(problems, fmt.Sprintf("scenario phase %s %s must be a duration, ie 90s or 5m, got %q", name, value.key, value.value))
+			}
+		}
+		if phase.Count < 0 {
+			problems = append(problems, fmt.Sprintf("scenario phase %s count must be >= 0, got %d", name, phase.Count))
+		}
+		if phase.Rate < 0 {
+			problems = append(problems, fmt.Sprintf("scenario phase %s rate must be >= 0 sales/second, got %g", name, phase.Rate))
+		}
+		for key := range phase.Set {
+			for _, setting := range scenarioSettings {
+				if strings.EqualFold(key, setting) {
+					problems = append(problems, fmt.Sprintf("scenario phase %s cannot set %s, it is the scenario's, shared by the phases, set it in the env's configuration", name, key))
+				}
+			}
+		}
+		for _, sink := range phase.Sinks {
+			if sink != sinkKafka && sink != sinkMongo && sink != sinkFile {
+				problems = append(problems, fmt.Sprintf("scenario phase %s sinks must be %s, %s or %s, got %q", name, sinkKafka, sinkMongo, sinkFile, sink))
+			}
+		}
+
+		// A phase that runs until Ctrl-C holds up the phase after it
+		if phase.Duration == "" && phase.Count == 0 && i < len(scenario.Phases)-1 && scenario.Phases[i+1].Start == "" {
+			problems = append(problems, fmt.Sprintf("scenario phase %s has no duration or count, the phase after it would never start", name))
+		}
+	}
+
+	return problems
+}
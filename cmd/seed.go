@@ -0,0 +1,171 @@
+/*****************************************************************************
+*
+*	File			: seed.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Seed file checks, run at startup, before anything is picked from the seed, and by seed validate.
+*
+*					: Problems stop the run: empty stores/clerks/products, missing ids, names and categories,
+*					: duplicate ids, negative weights and prices <= 0. The references into the seed, clerks' stores,
+*					: promotions' products etc., are checked by validateSeedConfig, each in its own file.
+*					: Warnings are reported, the run goes on: duplicate names, and prices far out of line with their
+*					: category (suspiciousPrice times the category median or more, either way) or with more than 2
+*					: decimals.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"cmd/types"
+)
+
+// A price this many times its category's median, or that fraction of it, is suspicious
+const suspiciousPrice = 20
+
+// The seed's own consistency, what would otherwise show up as odd data or an index out of range.
+func validateSeed(seed types.TPSeed) []string {
+
+	var problems []string
+
+	if len(seed.Stores) == 0 {
+		problems = append(problems, "seed has no Stores")
+	}
+	if len(seed.Clerks) == 0 {
+		problems = append(problems, "seed has no Clerks")
+	}
+	if len(seed.Products) == 0 {
+		problems = append(problems, "seed has no Products")
+	}
+
+	stores := make(map[string]bool)
+	for i, store := range seed.Stores {
+		switch {
+		case store.Id == "":
+			problems = append(problems, fmt.Sprintf("seed store %d (%s) has no id", i, store.Name))
+		case stores[store.Id]:
+			problems = append(problems, fmt.Sprintf("seed store %s (%s) id is duplicated", store.Id, store.Name))
+		}
+		stores[store.Id] = true
+
+		if store.Name == "" {
+			problems = append(problems, fmt.Sprintf("seed store %s has no name", store.Id))
+		}
+		if store.Weight < 0 {
+			problems = append(problems, fmt.Sprintf("seed store %s (%s) weight must be >= 0, got %g", store.Id, store.Name, store.Weight))
+		}
+	}
+
+	clerks := make(map[string]bool)
+	for i, clerk := range seed.Clerks {
+		switch {
+		case clerk.Id == "":
+			problems = append(problems, fmt.Sprintf("seed clerk %d (%s) has no id", i, clerk.Name))
+		case clerks[clerk.Id]:
+			problems = append(problems, fmt.Sprintf("seed clerk %s (%s) id is duplicated", clerk.Id, clerk.Name))
+		}
+		clerks[clerk.Id] = true
+
+		if clerk.Name == "" {
+			problems = append(problems, fmt.Sprintf("seed clerk %s has no name", clerk.Id))
+		}
+		if clerk.Weight < 0 {
+			problems = append(problems, fmt.Sprintf("seed clerk %s (%s) weight must be >= 0, got %g", clerk.Id, clerk.Name, clerk.Weight))
+		}
+	}
+
+	products := make(map[string]bool)
+	for i, product := range seed.Products {
+		switch {
+		case product.Id == "":
+			problems = append(problems, fmt.Sprintf("seed product %d (%s) has no id", i, product.Name))
+		case products[product.Id]:
+			problems = append(problems, fmt.Sprintf("seed product %s (%s) id is duplicated", product.Id, product.Name))
+		}
+		products[product.Id] = true
+
+		if product.Name == "" {
+			problems = append(problems, fmt.Sprintf("seed product %s has no name", product.Id))
+		}
+		if product.Category == "" {
+			problems = append(problems, fmt.Sprintf("seed product %s (%s) has no category", product.Id, product.Name))
+		}
+		if product.Price <= 0 {
+			problems = append(problems, fmt.Sprintf("seed product %s (%s) price must be > 0, got %g", product.Id, product.Name, product.Price))
+		}
+		if product.Weight < 0 {
+			problems = append(problems, fmt.Sprintf("seed product %s (%s) weight must be >= 0, got %g", product.Id, product.Name, product.Weight))
+		}
+	}
+
+	return problems
+}
+
+// What looks wrong, but need not be, in the seed.
+func lintSeed(seed types.TPSeed) []string {
+
+	var warnings []string
+
+	names := make(map[string]string)
+	for _, store := range seed.Stores {
+		if id, ok := names[store.Name]; ok && store.Name != "" {
+			warnings = append(warnings, fmt.Sprintf("seed store %s name %q is also store %s's", store.Id, store.Name, id))
+		}
+		names[store.Name] = store.Id
+	}
+
+	names = make(map[string]string)
+	prices := make(map[string][]float64)
+	for _, product := range seed.Products {
+		if id, ok := names[product.Name]; ok && product.Name != "" {
+			warnings = append(warnings, fmt.Sprintf("seed product %s name %q is also product %s's", product.Id, product.Name, id))
+		}
+		names[product.Name] = product.Id
+
+		if product.Price > 0 {
+			prices[product.Category] = append(prices[product.Category], product.Price)
+		}
+	}
+
+	median := make(map[string]float64)
+	for category, p := range prices {
+		sort.Float64s(p)
+		median[category] = p[len(p)/2]
+	}
+
+	for _, product := range seed.Products {
+		if product.Price <= 0 {
+			continue
+		}
+		if m := median[product.Category]; product.Price >= m*suspiciousPrice || product.Price <= m/suspiciousPrice {
+			warnings = append(warnings, fmt.Sprintf("seed product %s (%s) price %g is out of line with the %s median of %g", product.Id, product.Name, product.Price, product.Category, m))
+		}
+		if math.Abs(product.Price-toFixed(product.Price, 2)) > 1e-9 {
+			warnings = append(warnings, fmt.Sprintf("seed product %s (%s) price %g has more than 2 decimals", product.Id, product.Name, product.Price))
+		}
+	}
+
+	return warnings
+}
+
+// seed validate, loadSeed reports the seed's problems and warnings, then the references into it, exit 1 on problems.
+func runSeedValidate(opts *tpOptions) {
+
+	loadSettings(opts, false, false)
+	seed := loadSeed(vGeneral.SeedFile)
+
+	if problems := validateSeedConfig(vGeneral, seed); len(problems) > 0 {
+		fatalProblems("Configuration and Seed do not match:", problems)
+
+	}
+
+	grpcLog.Infoln("* Seed OK                     :", vGeneral.SeedFile)
+
+}
@@ -0,0 +1,380 @@
+/*****************************************************************************
+*
+*	File			: sinks.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Everywhere a basket and its payment can go, Kafka, Mongo, the json_save files and the run manifest,
+*					: as enabled in *_app.json. Shared by produce and replay. Inventory movements, see inventory.go, price
+*					: changes, see pricing.go, online order events, see orders.go, and anomaly labels, see anomalies.go, go to
+*					: the same sinks, each to their own topic, collection and file.
+*
+*					: --dry-run switches all of them off, the documents are rather printed to stdout, one JSON document
+*					: per line, so they can be piped into jq or a file.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+
+	"cmd/types"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type tpSinks struct {
+	dryRun      bool
+	kafka       *kafkaSink
+	mongoClient *mongo.Client
+	mongo       *mongoSink
+	f_basket    *os.File
+	f_pmnt      *os.File
+	f_inventory *os.File
+	f_price     *os.File
+	f_orders    *os.File
+	f_labels    *os.File
+	f_manifest  *os.File
+}
+
+func openOutputFile(kind string) *os.File {
+
+	fileName := fmt.Sprintf("%s%s%s_%s.json", vGeneral.Output_path, pathSep, runId, kind)
+	if kind == "manifest" {
+		fileName = manifestFileName(runId)
+	}
+
+	if vGeneral.Debuglevel > 2 {
+		grpcLog.Infoln(fmt.Sprintf("%-21s:", kind+" File"), fileName)
+
+	}
+
+	f, err := os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		grpcLog.Errorln("os.OpenFile error A", err)
+		panic(err)
+
+	}
+	return f
+}
+
+// Connect/open every enabled sink, as per vGeneral.
+func openSinks(dryRun bool) *tpSinks {
+
+	s := &tpSinks{dryRun: dryRun}
+
+	if dryRun {
+		return s
+	}
+
+	// if Kafka is enabled then create the confluent kafka connection session/objects
+	if vGeneral.KafkaEnabled == 1 {
+
+		// Lets make sure the topic/s exist
+		CreateTopic(vKafka)
+
+		k, err := newKafkaSink()
+		if err != nil {
+			grpcLog.Fatalln("Kafka Producer Creation Failed: ", err)
+
+		}
+		s.kafka = k
+	}
+
+	if vGeneral.MongoAtlasEnabled == 1 {
+
+		Mongoclient, err := connectMongo(vMongodb)
+		if err != nil {
+			grpcLog.Fatal("Mongo Connect Failed: ", err)
+		}
+		s.mongoClient = Mongoclient
+
+		// Define the Mongo Datastore
+		appLabDatabase := Mongoclient.Database(vMongodb.Datastore)
+		// Define the Mongo Collection Objects, as per the document Modelling chosen
+		s.mongo, err = newMongoSink(appLabDatabase, vMongodb)
+		if err != nil {
+			grpcLog.Fatal("Mongo Sink Creation Failed: ", err)
+		}
+
+		if vGeneral.Debuglevel > 0 {
+			grpcLog.Infoln("* MongoDB Datastore and Collections Intialized")
+			grpcLog.Infoln("*")
+		}
+	}
+
+	// each time we run, and say we want to store the data created to disk, we create a pair of files for that run.
+	// this runId is used as the file name, prepended to either _basket.json or _pmnt.json
+	if vGeneral.Json_to_file == 1 {
+		s.f_basket = openOutputFile("basket")
+		s.f_pmnt = openOutputFile("pmnt")
+		if varStock != nil && varStock.enabled() {
+			s.f_inventory = openOutputFile("inventory")
+		}
+		if varPrice != nil && varPrice.enabled() {
+			s.f_price = openOutputFile("price")
+		}
+		if varOrder != nil && varOrder.enabled() {
+			s.f_orders = openOutputFile("orders")
+		}
+		if varFraud != nil && varFraud.enabled() {
+			s.f_labels = openOutputFile("labels")
+		}
+	}
+
+	// Record every invoice produced, so the verify command can reconcile the Mongo collections against this run.
+	if vGeneral.Manifest == 1 {
+		s.f_manifest = openOutputFile("manifest")
+	}
+
+	return s
+}
+
+// Flush whatever is still queued and close everything we opened.
+func (s *tpSinks) close() {
+
+	if s.kafka != nil {
+		s.kafka.close()
+	}
+
+	if s.mongo != nil {
+		// Write the trailing partial batch
+		s.mongo.flush()
+		if err := s.mongoClient.Disconnect(context.TODO()); err != nil {
+			grpcLog.Fatal("Mongo Disconected: ", err)
+		}
+	}
+
+	for _, f := range []*os.File{s.f_basket, s.f_pmnt, s.f_inventory, s.f_price, s.f_orders, s.f_labels, s.f_manifest} {
+		if f != nil {
+			f.Close()
+		}
+	}
+}
+
+// Hand the basket and its payment to every enabled sink.
+func (s *tpSinks) post(sale_Basket *types.Pb_Basket, sale_Payment *types.Pb_Payment) {
+
+	// When the documents are handed to the sinks, latency measures from here to their arrival in Mongo.
+	produceTimestamp := time.Now().UnixMicro()
+	sale_Basket.ProduceTimestamp = produceTimestamp
+	sale_Payment.ProduceTimestamp = produceTimestamp
+
+	// Data-quality faults, a share of the sales get a copy of their basket or payment corrupted, see faults.go, every
+	// sink gets the corrupted copy, the sale itself goes into the manifest and the Mongo upsert filter.
+	fault, pb_Basket, pb_Payment := varFault.inject(sale_Basket, sale_Payment)
+
+	json_SalesBasket, err := json.Marshal(pb_Basket)
+	if err != nil {
+		grpcLog.Fatalln("json_SalesBasket Marshal: ", err)
+
+	}
+	json_SalesBasket = fault.corrupt(faultBasket, json_SalesBasket)
+
+	json_Payment, err := json.Marshal(pb_Payment)
+	if err != nil {
+		grpcLog.Fatalln("json_Payment Marshal: ", err)
+
+	}
+	json_Payment = fault.corrupt(faultPayment, json_Payment)
+
+	if s.dryRun {
+		fmt.Println(string(json_SalesBasket))
+		fmt.Println(string(json_Payment))
+		return
+
+	}
+
+	// echo to screen
+	if vGeneral.Debuglevel >= 2 {
+		prettyJSON(string(json_SalesBasket))
+		prettyJSON(string(json_Payment))
+	}
+
+	// Post to Confluent Kafka - if enabled
+	if s.kafka != nil {
+		s.kafka.postSale(pb_Basket, pb_Payment, fault, json_SalesBasket, json_Payment)
+
+	}
+
+	// Do we want to insertrecords/documents directly into Mongo Atlas?
+	if s.mongo != nil {
+		s.mongo.write(sale_Payment.InvoiceNumber, json_SalesBasket, json_Payment)
+
+	}
+
+	// Save multiple Basket docs and Payment docs to a single basket file and single payment file for the run
+	if s.f_basket != nil {
+
+		if vGeneral.Debuglevel >= 2 {
+			grpcLog.Info("")
+			grpcLog.Info("JSON to File Flow")
+
+		}
+
+		// Basket, indented from the JSON, faults and all
+		var pretty_basket bytes.Buffer
+		if err := json.Indent(&pretty_basket, json_SalesBasket, "", " "); err != nil {
+			grpcLog.Errorln(fmt.Sprintf("pretty_basket Indent error %s", err))
+
+		}
+
+		if _, err = s.f_basket.WriteString(pretty_basket.String() + ",\n"); err != nil {
+			grpcLog.Errorln(fmt.Sprintf("os.WriteString error %s", err))
+
+		}
+
+		// Payment
+		var pretty_pmnt bytes.Buffer
+		if err := json.Indent(&pretty_pmnt, json_Payment, "", " "); err != nil {
+			grpcLog.Errorln(fmt.Sprintf("pretty_pmnt Indent error %s", err))
+
+		}
+
+		if _, err = s.f_pmnt.WriteString(pretty_pmnt.String() + ",\n"); err != nil {
+			grpcLog.Errorln(fmt.Sprintf("pretty_pmnt os.WriteString error %s", err))
+
+		}
+	}
+
+	if s.f_manifest != nil {
+		writeManifest(s.f_manifest, sale_Basket, sale_Payment, fault)
+
+	}
+}
+
+// Hand the inventory movements to every enabled sink, keyed by store name, so a store's movements stay in order.
+func (s *tpSinks) postMovements(movements []*types.PBInventoryMovement) {
+
+	postDocs(s, "inventory", vKafka.InventoryTopicname, func(m *types.PBInventoryMovement) string {
+		return m.StoreName
+	}, movements, s.f_inventory)
+}
+
+// Hand the price changes to every enabled sink, keyed by product id, and price list if not the seed prices', so a
+// product's prices stay in order.
+func (s *tpSinks) postPriceChanges(changes []*types.PBPriceChange) {
+
+	postDocs(s, "price", vKafka.PriceTopicname, func(c *types.PBPriceChange) string {
+		if c.PriceList != "" {
+			return fmt.Sprintf("%s/%s", c.ProductId, c.PriceList)
+		}
+		return c.ProductId
+	}, changes, s.f_price)
+}
+
+// Hand the online order events to every enabled sink, keyed by order id, so an order's events stay in order.
+func (s *tpSinks) postOrderEvents(events []*types.PBOrderEvent) {
+
+	postDocs(s, "orders", vKafka.OrderTopicname, func(e *types.PBOrderEvent) string {
+		return e.OrderId
+	}, events, s.f_orders)
+}
+
+// Hand the anomaly labels to every enabled sink, keyed by invoice number, to join to the basket.
+func (s *tpSinks) postLabels(labels []*types.PBAnomalyLabel) {
+
+	postDocs(s, "labels", vKafka.LabelTopicname, func(l *types.PBAnomalyLabel) string {
+		return l.InvoiceNumber
+	}, labels, s.f_labels)
+}
+
+// Stamp the documents of the kind with their produceTimestamp and hand them to every enabled sink that has a place
+// for them, the Kafka topic, keyed by key, the kind's Mongo collection, see mongoSink.writeDoc, and the json_save file.
+func postDocs[T proto.Message](s *tpSinks, kind string, topic string, key func(T) string, docs []T, f *os.File) {
+
+	produceTimestamp := time.Now().UnixMicro()
+	for _, doc := range docs {
+		m := doc.ProtoReflect()
+		m.Set(m.Descriptor().Fields().ByName("produceTimestamp"), protoreflect.ValueOfInt64(produceTimestamp))
+
+		json_Doc, err := json.Marshal(doc)
+		if err != nil {
+			grpcLog.Fatalln(fmt.Sprintf("json_%s Marshal: ", kind), err)
+
+		}
+
+		if s.dryRun {
+			fmt.Println(string(json_Doc))
+			continue
+
+		}
+
+		if vGeneral.Debuglevel >= 2 {
+			prettyJSON(string(json_Doc))
+		}
+
+		if s.kafka != nil && topic != "" {
+			if err := s.kafka.produce(topic, key(doc), doc); err != nil {
+				grpcLog.Fatalf("%s: %s", kind, err)
+
+			}
+		}
+
+		if s.mongo != nil {
+			s.mongo.writeDoc(kind, json_Doc)
+
+		}
+
+		if f != nil {
+			pretty_Doc, err := json.MarshalIndent(doc, "", " ")
+			if err != nil {
+				grpcLog.Errorln(fmt.Sprintf("pretty_%s MarshalIndent error %s", kind, err))
+
+			}
+
+			if _, err = f.WriteString(string(pretty_Doc) + ",\n"); err != nil {
+				grpcLog.Errorln(fmt.Sprintf("pretty_%s os.WriteString error %s", kind, err))
+
+			}
+		}
+	}
+}
+
+// Paces the records, either at a fixed Rate (records/second) or, if Rate is 0, the random 0..Sleep ms pause,
+// both scaled by the traffic demand.
+type tpPacer struct {
+	rate float64
+	next time.Time
+}
+
+func newPacer(rate float64) *tpPacer {
+	return &tpPacer{rate: rate, next: time.Now()}
+}
+
+// Wait until the next record is due, demand being the relative traffic right now, 1 => as configured.
+func (p *tpPacer) wait(demand float64) {
+
+	if p.rate > 0 {
+		p.next = p.next.Add(time.Duration(float64(time.Second) / (p.rate * demand)))
+
+		// Don't burst to catch up after a stall, ie waiting for a store to open
+		if time.Until(p.next) < -time.Second {
+			p.next = time.Now()
+		}
+		time.Sleep(time.Until(p.next))
+		return
+
+	}
+
+	// used to slow the data production/posting to kafka and safe to file system down.
+	if vGeneral.Sleep > 0 {
+		n := int(float64(rand.Intn(vGeneral.Sleep)) / demand) // if vGeneral.sleep = 1000, then n will be random value of 0 -> 1000  aka 0 and 1 second
+		if vGeneral.Debuglevel >= 2 {
+			grpcLog.Infof("Going to sleep for            : %d Milliseconds\n", n)
+
+		}
+		time.Sleep(time.Duration(n) * time.Millisecond)
+	}
+}
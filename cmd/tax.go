@@ -0,0 +1,132 @@
+/*****************************************************************************
+*
+*	File			: tax.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Tax per basket line, as per the product's tax category:
+*					:	standard	- Vatrate
+*					:	zero		- zero rated, taxable at 0%
+*					:	exempt		- not taxable
+*
+*					: The seed "tax" section can set the rates, or add tax categories of its own, and map product
+*					: categories onto tax categories, a product's own taxCategory wins, otherwise standard:
+*
+*					: "tax": {"rates": {"standard": 0.15, "zero": 0, "exempt": 0},
+*					:         "categories": {"Fresh Produce": "zero"}}
+*
+*					: With Tax_inclusive = 1 the seed prices include the tax, the line tax is then the tax fraction of the
+*					: line, otherwise it is added on top. Either way the basket vat is the sum of the line taxes, nett the
+*					: lines after discount excluding tax, and total = nett + vat.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"cmd/types"
+)
+
+const (
+	taxStandard = "standard"
+	taxZero     = "zero"
+	taxExempt   = "exempt"
+)
+
+type tpTax struct {
+	rates      map[string]float64
+	categories map[string]string
+}
+
+// The built in rates, standard = Vatrate, with the seed rates laid over them.
+func taxRates(app types.Tp_general, seed types.TPSeed) map[string]float64 {
+
+	rates := map[string]float64{taxStandard: app.Vatrate, taxZero: 0, taxExempt: 0}
+	for category, rate := range seed.Tax.Rates {
+		rates[category] = rate
+	}
+	return rates
+}
+
+func newTax(seed types.TPSeed) *tpTax {
+	return &tpTax{rates: taxRates(vGeneral, seed), categories: seed.Tax.Categories}
+}
+
+// The product's tax category, its own, else its category's, else standard.
+func (t *tpTax) category(product types.TProductStruct) string {
+
+	if product.TaxCategory != "" {
+		return product.TaxCategory
+	}
+	if category, ok := t.categories[product.Category]; ok {
+		return category
+	}
+	return taxStandard
+}
+
+// Tax the basket items, the lines after discount, returns the basket nett (excluding tax) and vat.
+func (t *tpTax) apply(items []*types.BasketItem) (nett float64, vat float64) {
+
+	for _, item := range items {
+		line := item.Price*float64(item.Quantity) - item.Discount
+		rate := t.rates[item.TaxCategory]
+
+		item.TaxRate = rate
+		if vGeneral.Tax_inclusive == 1 {
+			item.Tax = toFixed(line*rate/(1+rate), 2)
+			line = line - item.Tax
+
+		} else {
+			item.Tax = toFixed(line*rate, 2)
+
+		}
+
+		nett += line
+		vat += item.Tax
+	}
+
+	return toFixed(nett, 2), toFixed(vat, 2)
+}
+
+// The seed tax rates are fractions, and the product and category tax categories have a rate.
+func validateTax(app types.Tp_general, seed types.TPSeed) []string {
+
+	var problems []string
+
+	rates := taxRates(app, seed)
+
+	var names []string
+	for name := range seed.Tax.Rates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if rate := seed.Tax.Rates[name]; rate < 0 || rate >= 1 {
+			problems = append(problems, fmt.Sprintf("seed tax rate %s must be a fraction between 0 and 1, ie 0.15, got %g", name, rate))
+		}
+	}
+
+	names = nil
+	for category := range seed.Tax.Categories {
+		names = append(names, category)
+	}
+	sort.Strings(names)
+	for _, category := range names {
+		if _, ok := rates[seed.Tax.Categories[category]]; !ok {
+			problems = append(problems, fmt.Sprintf("seed tax categories %s => %q has no rate", category, seed.Tax.Categories[category]))
+		}
+	}
+
+	for _, product := range seed.Products {
+		if _, ok := rates[product.TaxCategory]; product.TaxCategory != "" && !ok {
+			problems = append(problems, fmt.Sprintf("seed product %s (%s) taxCategory %q has no rate", product.Id, product.Name, product.TaxCategory))
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,241 @@
+/*****************************************************************************
+*
+*	File			: terminals.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Checkout points per store, from the seed store "terminals", else *_app.json Terminals tills.
+*					: The terminal type decides the basket size and the payment method mix:
+*					:	till			- 1..Max_items_basket items, card 55%, cash 35%, mobile 10%
+*					:	self-checkout	- 1..Max_items_basket/2 items, card 75%, mobile 25%
+*					:	online			- Max_items_basket/2..Max_items_basket*2 items, card 70%, eft 20%, mobile 10%
+*
+*					: The seed "terminalTypes" override these, or add types of their own, ie
+*					: {"type": "kiosk", "minItems": 1, "maxItems": 3, "paymentMethods": {"card": 1}}
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"cmd/types"
+)
+
+const (
+	terminalTill         = "till"
+	terminalSelfCheckout = "self-checkout"
+	terminalOnline       = "online"
+)
+
+// Number of tills for stores without seed terminals, when Terminals = 0
+const defaultTerminals = 20
+
+// The built in terminal types, as per Max_items_basket
+func builtinTerminalTypes() map[string]types.TPTerminalType {
+
+	maxItems := vGeneral.Max_items_basket
+	halfItems := maxItems / 2
+	if halfItems < 1 {
+		halfItems = 1
+	}
+
+	return map[string]types.TPTerminalType{
+		terminalTill: {
+			Type:           terminalTill,
+			MinItems:       1,
+			MaxItems:       maxItems,
+			PaymentMethods: map[string]float64{"card": 0.55, "cash": 0.35, "mobile": 0.10},
+		},
+		terminalSelfCheckout: {
+			Type:           terminalSelfCheckout,
+			MinItems:       1,
+			MaxItems:       halfItems,
+			PaymentMethods: map[string]float64{"card": 0.75, "mobile": 0.25},
+		},
+		terminalOnline: {
+			Type:           terminalOnline,
+			MinItems:       halfItems,
+			MaxItems:       maxItems * 2,
+			PaymentMethods: map[string]float64{"card": 0.70, "eft": 0.20, "mobile": 0.10},
+		},
+	}
+}
+
+// The built in terminal types, with the seed terminalTypes laid over them, a seed type's missing values are the built in ones.
+func terminalTypes(seed types.TPSeed) map[string]types.TPTerminalType {
+
+	profiles := builtinTerminalTypes()
+
+	for _, t := range seed.TerminalTypes {
+		profile, ok := profiles[t.Type]
+		if !ok {
+			profile = profiles[terminalTill]
+			profile.Type = t.Type
+		}
+		if t.MinItems > 0 {
+			profile.MinItems = t.MinItems
+		}
+		if t.MaxItems > 0 {
+			profile.MaxItems = t.MaxItems
+		}
+		if len(t.PaymentMethods) > 0 {
+			profile.PaymentMethods = t.PaymentMethods
+		}
+		if profile.MinItems > profile.MaxItems {
+			profile.MinItems = profile.MaxItems
+		}
+		profiles[t.Type] = profile
+	}
+	return profiles
+}
+
+// The store's terminals, as per the seed, else Terminals tills numbered 1..Terminals
+func storeTerminals(store types.TPStoreStruct) []types.TPTerminalStruct {
+
+	if len(store.Terminals) > 0 {
+		return store.Terminals
+	}
+
+	count := vGeneral.Terminals
+	if count == 0 {
+		count = defaultTerminals
+	}
+
+	terminals := make([]types.TPTerminalStruct, count)
+	for i := range terminals {
+		terminals[i] = types.TPTerminalStruct{Id: strconv.Itoa(i + 1), Type: terminalTill}
+	}
+	return terminals
+}
+
+// A payment method picker per terminal type
+type tpPaymentMethods struct {
+	methods []string
+	picker  *tpPicker
+}
+
+func newPaymentMethods(profile types.TPTerminalType) tpPaymentMethods {
+
+	p := tpPaymentMethods{}
+	for method := range profile.PaymentMethods {
+		p.methods = append(p.methods, method)
+	}
+	sort.Strings(p.methods)
+
+	var weights []float64
+	for _, method := range p.methods {
+		weights = append(weights, profile.PaymentMethods[method])
+	}
+	p.picker = newPicker(weights)
+
+	return p
+}
+
+func (p tpPaymentMethods) pick() string {
+	return p.methods[p.picker.pick()]
+}
+
+// Everything needed to pick a terminal for a store and then size and pay for the basket.
+type tpTerminals struct {
+	profiles  map[string]types.TPTerminalType
+	payments  map[string]tpPaymentMethods
+	terminals [][]types.TPTerminalStruct // per store position
+	pickers   []*tpPicker                // per store position
+}
+
+func newTerminals(seed types.TPSeed) *tpTerminals {
+
+	t := &tpTerminals{
+		profiles: terminalTypes(seed),
+		payments: make(map[string]tpPaymentMethods),
+	}
+
+	for name, profile := range t.profiles {
+		t.payments[name] = newPaymentMethods(profile)
+	}
+
+	for _, store := range seed.Stores {
+		terminals := storeTerminals(store)
+
+		var weights []float64
+		for _, terminal := range terminals {
+			weights = append(weights, terminal.Weight)
+		}
+
+		t.terminals = append(t.terminals, terminals)
+		t.pickers = append(t.pickers, newPicker(seedWeights(weights, vGeneral.Distribution)))
+	}
+
+	return t
+}
+
+// One of the store's terminals, and its type's profile
+func (t *tpTerminals) pick(store int) (types.TPTerminalStruct, types.TPTerminalType) {
+
+	terminal := t.terminals[store][t.pickers[store].pick()]
+	return terminal, t.profiles[terminal.Type]
+}
+
+func (t *tpTerminals) paymentMethod(terminalType string) string {
+	return t.payments[terminalType].pick()
+}
+
+// Every terminal of a known type, unique per store, and the seed terminal types make sense.
+func validateTerminals(app types.Tp_general, seed types.TPSeed) []string {
+
+	var problems []string
+
+	if app.Terminals < 0 {
+		problems = append(problems, fmt.Sprintf("app.Terminals must be >= 0 (0 => %d), got %d", defaultTerminals, app.Terminals))
+	}
+
+	known := map[string]bool{terminalTill: true, terminalSelfCheckout: true, terminalOnline: true}
+	for _, t := range seed.TerminalTypes {
+		known[t.Type] = true
+
+		if t.Type == "" {
+			problems = append(problems, "seed terminalTypes entry has no type")
+		}
+		if t.MinItems < 0 || t.MaxItems < 0 || (t.MaxItems > 0 && t.MinItems > t.MaxItems) {
+			problems = append(problems, fmt.Sprintf("seed terminal type %s needs 0 < minItems <= maxItems, got %d..%d", t.Type, t.MinItems, t.MaxItems))
+		}
+
+		total := 0.0
+		for method, w := range t.PaymentMethods {
+			if w < 0 {
+				problems = append(problems, fmt.Sprintf("seed terminal type %s payment method %s weight must be >= 0, got %g", t.Type, method, w))
+			}
+			total += w
+		}
+		if len(t.PaymentMethods) > 0 && total <= 0 {
+			problems = append(problems, fmt.Sprintf("seed terminal type %s payment method weights add up to 0", t.Type))
+		}
+	}
+
+	for _, store := range seed.Stores {
+		ids := make(map[string]bool)
+		for _, terminal := range store.Terminals {
+			if terminal.Id == "" {
+				problems = append(problems, fmt.Sprintf("seed store %s (%s) has a terminal without an id", store.Id, store.Name))
+			} else if ids[terminal.Id] {
+				problems = append(problems, fmt.Sprintf("seed store %s (%s) terminal %s is duplicated", store.Id, store.Name, terminal.Id))
+			}
+			ids[terminal.Id] = true
+
+			if !known[terminal.Type] {
+				problems = append(problems, fmt.Sprintf("seed store %s (%s) terminal %s type must be %s, %s, %s or a terminalTypes type, got %q", store.Id, store.Name, terminal.Id, terminalTill, terminalSelfCheckout, terminalOnline, terminal.Type))
+			}
+			if terminal.Weight < 0 {
+				problems = append(problems, fmt.Sprintf("seed store %s (%s) terminal %s weight must be >= 0, got %g", store.Id, store.Name, terminal.Id, terminal.Weight))
+			}
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,117 @@
+/*****************************************************************************
+*
+*	File			: timezones.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Sales happen in the store's local time. A seed store can carry an IANA timezone, ie
+*					: {"id": "324213412", "name": "Rosebank", "timezone": "Africa/Johannesburg"}, stores without one
+*					: are in *_app.json Timezone, blank => the machine's local zone. The offset is worked out per event,
+*					: so daylight saving is honoured, shifts and opening hours are store local times.
+*
+*					: saleDateTime and payDateTime are written as per TimestampFormat:
+*					:	rfc3339		- 2026-10-18T14:05:09.123+02:00 (default)
+*					:	epoch_ms	- milliseconds since 1970-01-01 UTC
+*					:	epoch_us	- microseconds since 1970-01-01 UTC
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"cmd/types"
+)
+
+const (
+	timestampRFC3339 = "rfc3339"
+	timestampEpochMs = "epoch_ms"
+	timestampEpochUs = "epoch_us"
+)
+
+// RFC3339 with milliseconds
+const rfc3339Milli = "2006-01-02T15:04:05.000Z07:00"
+
+// Loaded locations by IANA name
+var zones = struct {
+	sync.Mutex
+	m map[string]*time.Location
+}{m: make(map[string]*time.Location)}
+
+// The location of an IANA zone name, blank => Timezone, blank => the machine's local zone.
+// Names are validated at startup, an unknown one here falls back to local time.
+func zone(name string) *time.Location {
+
+	if name == "" {
+		name = vGeneral.Timezone
+	}
+	if name == "" {
+		return time.Local
+	}
+
+	zones.Lock()
+	defer zones.Unlock()
+
+	if loc, ok := zones.m[name]; ok {
+		return loc
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		loc = time.Local
+	}
+	zones.m[name] = loc
+	return loc
+}
+
+// t in the store's local time
+func storeTime(store types.TPStoreStruct, t time.Time) time.Time {
+	return t.In(zone(store.Timezone))
+}
+
+// saleDateTime/payDateTime as per TimestampFormat
+func formatTimestamp(t time.Time) string {
+
+	switch vGeneral.TimestampFormat {
+	case timestampEpochMs:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+
+	case timestampEpochUs:
+		return strconv.FormatInt(t.UnixMicro(), 10)
+
+	default:
+		return t.Format(rfc3339Milli)
+	}
+}
+
+// Timezone and TimestampFormat, and every seed store timezone, are known.
+func validateZones(app types.Tp_general, seed types.TPSeed) []string {
+
+	var problems []string
+
+	switch app.TimestampFormat {
+	case "", timestampRFC3339, timestampEpochMs, timestampEpochUs:
+	default:
+		problems = append(problems, fmt.Sprintf("app.TimestampFormat must be %s, %s or %s, got %q", timestampRFC3339, timestampEpochMs, timestampEpochUs, app.TimestampFormat))
+	}
+
+	if _, err := time.LoadLocation(app.Timezone); err != nil {
+		problems = append(problems, fmt.Sprintf("app.Timezone must be an IANA zone, ie Africa/Johannesburg, got %q", app.Timezone))
+	}
+
+	for _, store := range seed.Stores {
+		if store.Timezone == "" {
+			continue
+		}
+		if _, err := time.LoadLocation(store.Timezone); err != nil {
+			problems = append(problems, fmt.Sprintf("seed store %s (%s) timezone must be an IANA zone, ie Africa/Johannesburg, got %q", store.Id, store.Name, store.Timezone))
+		}
+	}
+
+	return problems
+}
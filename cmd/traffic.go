@@ -0,0 +1,300 @@
+/*****************************************************************************
+*
+*	File			: traffic.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Traffic model, when and how much people buy, configured by the seed "traffic" section:
+*
+*					: "traffic": {
+*					:   "hourly": [0.1, 0.1, ... 24 values, relative demand per hour of the day],
+*					:   "rules": [
+*					:     {"name": "weekend", "days": ["sat", "sun"], "demand": 1.3, "items": 1.2},
+*					:     {"name": "festive", "months": ["dec"], "demand": 1.5},
+*					:     {"name": "Black Friday", "from": "2026-11-27", "to": "2026-11-27", "demand": 3, "items": 1.5,
+*					:      "categories": {"Beverage": 2}}
+*					:   ]
+*					: }
+*
+*					: The demand at a moment is the hourly value times the demand of every rule that matches, a rule
+*					: matches if all of its days, months and from..to dates do, times the share of the store traffic
+*					: that is open (seed store "hours") with a clerk on shift. It scales the Rate, or shortens the Sleep,
+*					: when nothing is open we wait. items scales the basket size, categories the odds of the products
+*					: in those categories. No traffic section => demand 1, the run as before.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"cmd/types"
+)
+
+var months = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+	"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+	"sep": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+}
+
+const trafficDate = "2006-01-02"
+
+// The traffic at a moment in time
+type tpTrafficAt struct {
+	demand   float64   // relative event rate, 0 => everything is closed
+	items    float64   // basket size multiplier
+	products *tpPicker // product picker with the category boosts of the matching rules
+}
+
+type tpTraffic struct {
+	seed     types.TPSeed
+	hourly   []float64
+	rules    []types.TPTrafficRule
+	products map[string]*tpPicker // product pickers per set of matching rules with category boosts
+}
+
+func newTraffic(seed types.TPSeed) *tpTraffic {
+
+	t := &tpTraffic{
+		seed:     seed,
+		hourly:   seed.Traffic.Hourly,
+		rules:    seed.Traffic.Rules,
+		products: map[string]*tpPicker{"": varPick.products},
+	}
+
+	// Normalise the hourly curve to a mean of 1, so Rate stays the average rate over the day.
+	if len(t.hourly) == 24 {
+		sum := 0.0
+		for _, h := range t.hourly {
+			sum += h
+		}
+		t.hourly = make([]float64, 24)
+		for i, h := range seed.Traffic.Hourly {
+			t.hourly[i] = h * 24 / sum
+		}
+	}
+	return t
+}
+
+func ruleMatches(rule types.TPTrafficRule, at time.Time) bool {
+
+	if len(rule.Days) > 0 && !onDay(rule.Days, at.Weekday()) {
+		return false
+	}
+
+	if len(rule.Months) > 0 {
+		match := false
+		for _, m := range rule.Months {
+			if months[strings.ToLower(m)] == at.Month() {
+				match = true
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+
+	// from and to are whole days, in the time's own location
+	day := at.Format(trafficDate)
+	if rule.From != "" && day < rule.From {
+		return false
+	}
+	if rule.To != "" && day > rule.To {
+		return false
+	}
+	return true
+}
+
+// Is the store open at t, as per its seed hours in store local time, no hours => always.
+func storeOpen(store types.TPStoreStruct, at time.Time) bool {
+
+	if len(store.Hours) == 0 {
+		return true
+	}
+	at = storeTime(store, at)
+	for _, hours := range store.Hours {
+		if onShift(hours, at) {
+			return true
+		}
+	}
+	return false
+}
+
+// Share of the store traffic that is open, with a clerk on shift, at t.
+func (t *tpTraffic) openShare(at time.Time) float64 {
+
+	if vGeneral.Store != 0 {
+		if storeOpen(t.seed.Stores[vGeneral.Store], at) {
+			return 1
+		}
+		return 0
+	}
+
+	share := 0.0
+	for i, store := range t.seed.Stores {
+		if positions, _ := clerksOnShift(t.seed, store, at); len(positions) > 0 && storeOpen(store, at) {
+			share += varPick.stores.share(i)
+		}
+	}
+	return share
+}
+
+// The traffic at t, the hourly curve and rules are in Timezone, the store hours in their own.
+func (t *tpTraffic) at(at time.Time) tpTrafficAt {
+
+	traffic := tpTrafficAt{demand: 1, items: 1}
+	at = at.In(zone(""))
+
+	if len(t.hourly) == 24 {
+		traffic.demand = t.hourly[at.Hour()]
+	}
+
+	var boosted []int
+	for i, rule := range t.rules {
+		if !ruleMatches(rule, at) {
+			continue
+		}
+		if rule.Demand > 0 {
+			traffic.demand *= rule.Demand
+		}
+		if rule.Items > 0 {
+			traffic.items *= rule.Items
+		}
+		if len(rule.Categories) > 0 {
+			boosted = append(boosted, i)
+		}
+	}
+
+	traffic.demand *= t.openShare(at)
+	traffic.products = t.productPicker(boosted)
+
+	return traffic
+}
+
+// The product picker with the category boosts of the matching rules, built once per combination.
+func (t *tpTraffic) productPicker(boosted []int) *tpPicker {
+
+	key := fmt.Sprint(boosted)
+	if len(boosted) == 0 {
+		key = ""
+	}
+	if p, ok := t.products[key]; ok {
+		return p
+	}
+
+	boost := make(map[string]float64)
+	for _, i := range boosted {
+		for category, factor := range t.rules[i].Categories {
+			if _, ok := boost[category]; !ok {
+				boost[category] = 1
+			}
+			boost[category] *= factor
+		}
+	}
+
+	weights := make([]float64, len(t.seed.Products))
+	for i, product := range t.seed.Products {
+		weights[i] = varPick.products.share(i)
+		if factor, ok := boost[product.Category]; ok {
+			weights[i] *= factor
+		}
+	}
+
+	t.products[key] = newPicker(weights)
+	return t.products[key]
+}
+
+// Scale the basket size by the traffic items multiplier, at least 1 item.
+func (a tpTrafficAt) basketItems(n int) int {
+
+	scaled := int(math.Round(float64(n) * a.items))
+	if scaled < 1 {
+		return 1
+	}
+	return scaled
+}
+
+// Wait for a store to open, checking every minute, returns the traffic, and the time, once there is demand.
+func (t *tpTraffic) waitOpen() (tpTrafficAt, time.Time) {
+
+	now := time.Now()
+	traffic := t.at(now)
+	for traffic.demand <= 0 {
+		if vGeneral.Debuglevel > 0 {
+			grpcLog.Infoln("All stores closed, waiting   :", now.Format("Mon 15:04"))
+
+		}
+		time.Sleep(time.Minute)
+		now = time.Now()
+		traffic = t.at(now)
+	}
+	return traffic, now
+}
+
+// The hourly curve and rules make sense
+func validateTraffic(seed types.TPSeed) []string {
+
+	var problems []string
+	traffic := seed.Traffic
+
+	if len(traffic.Hourly) > 0 {
+		sum := 0.0
+		for _, h := range traffic.Hourly {
+			if h < 0 {
+				problems = append(problems, fmt.Sprintf("seed traffic hourly values must be >= 0, got %g", h))
+			}
+			sum += h
+		}
+		if len(traffic.Hourly) != 24 {
+			problems = append(problems, fmt.Sprintf("seed traffic hourly must have 24 values, one per hour, got %d", len(traffic.Hourly)))
+		} else if sum <= 0 {
+			problems = append(problems, "seed traffic hourly values add up to 0")
+		}
+	}
+
+	for _, rule := range traffic.Rules {
+		if rule.Demand < 0 || rule.Items < 0 {
+			problems = append(problems, fmt.Sprintf("seed traffic rule %s demand and items must be >= 0 (0 => 1), got %g and %g", rule.Name, rule.Demand, rule.Items))
+		}
+		for _, d := range rule.Days {
+			if _, ok := weekdays[strings.ToLower(d)]; !ok {
+				problems = append(problems, fmt.Sprintf("seed traffic rule %s day must be sun, mon, tue, wed, thu, fri or sat, got %q", rule.Name, d))
+			}
+		}
+		for _, m := range rule.Months {
+			if _, ok := months[strings.ToLower(m)]; !ok {
+				problems = append(problems, fmt.Sprintf("seed traffic rule %s month must be jan..dec, got %q", rule.Name, m))
+			}
+		}
+		for _, date := range []string{rule.From, rule.To} {
+			if _, err := time.Parse(trafficDate, date); date != "" && err != nil {
+				problems = append(problems, fmt.Sprintf("seed traffic rule %s from/to must be YYYY-MM-DD, got %q", rule.Name, date))
+			}
+		}
+		var categories []string
+		for category, factor := range rule.Categories {
+			if factor < 0 {
+				categories = append(categories, category)
+			}
+		}
+		sort.Strings(categories)
+		for _, category := range categories {
+			problems = append(problems, fmt.Sprintf("seed traffic rule %s category %s factor must be >= 0", rule.Name, category))
+		}
+	}
+
+	for _, store := range seed.Stores {
+		for _, hours := range store.Hours {
+			problems = append(problems, validateShift(fmt.Sprintf("seed store %s (%s) hours", store.Id, store.Name), hours)...)
+		}
+	}
+
+	return problems
+}
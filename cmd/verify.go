@@ -0,0 +1,426 @@
+/*****************************************************************************
+*
+*	File			: verify.go
+*
+* 	Created			: 18 Oct 2026
+*
+*	Description		: Reconcile a producer run against what actually landed in the Mongo Datastore, be that via the
+*					: Kafka sink connectors or the direct inserts. The producer records every invoiceNumber of a run
+*					: into <Output_path>/<runId>_manifest.json (*_app.json Manifest = 1), verify reads that back and
+*					: reports the missing, duplicated and mismatched documents, per the *_mongo.json Modelling.
+*
+*					: go run ./cmd verify --env <env> --run-id <runId>
+*
+*					: Invoices the manifest has as faulty, Faults > 0, see faults.go, are expected to be missing or
+*					: mismatched, those are reported as faulted rather than failing the verify.
+*
+*					: If Verify_wait > 0 and documents are missing or mismatched we open a change stream on the Datastore
+*					: and wait up to that many seconds for documents still in flight before the final reconcile.
+*
+*	Git				: https://github.com/georgelza/MongoCreator-GoProducer
+*
+*****************************************************************************/
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"cmd/types"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// invoiceNumbers per $in query
+const verifyChunk = 1000
+
+// One collection to reconcile, and the fields on its documents we compare against the manifest.
+type tpCheck struct {
+	name   string
+	col    *mongo.Collection
+	fields map[string]func(e types.TPManifestEntry) interface{}
+}
+
+type tpVerifyResult struct {
+	name       string
+	expected   int
+	found      int
+	missing    []string
+	duplicated []string
+	mismatched []string
+	faulted    []string // missing or mismatched as expected, the manifest has them as faulty, see faults.go
+	pending    []string // missing or mismatched invoiceNumbers, worth waiting for
+}
+
+func manifestFileName(runId string) string {
+	return fmt.Sprintf("%s%s%s_%s.json", vGeneral.Output_path, pathSep, runId, "manifest")
+}
+
+// Append the basket/payment pair to the run manifest, one JSON document per line, the sale's clean values and the
+// fault, if any, its posted documents have.
+func writeManifest(f *os.File, pb_Basket *types.Pb_Basket, pb_Payment *types.Pb_Payment, fault tpFault) {
+
+	entry := types.TPManifestEntry{
+		InvoiceNumber:    pb_Basket.InvoiceNumber,
+		Total:            pb_Basket.Total,
+		Paid:             pb_Payment.Paid,
+		FinTransactionID: pb_Payment.FinTransactionID,
+		Fault:            fault.kind,
+		FaultDoc:         fault.doc,
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		grpcLog.Errorln(fmt.Sprintf("manifest Marshal error %s", err))
+		return
+
+	}
+
+	if _, err = f.WriteString(string(line) + "\n"); err != nil {
+		grpcLog.Errorln(fmt.Sprintf("manifest os.WriteString error %s", err))
+
+	}
+}
+
+func loadManifest(fileName string) ([]types.TPManifestEntry, error) {
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []types.TPManifestEntry
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var entry types.TPManifestEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("manifest line %d: %w", len(entries)+1, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// Which collections hold what, as per the document Modelling used when the run was produced.
+func verifyChecks(appLabDatabase *mongo.Database) []tpCheck {
+
+	total := func(e types.TPManifestEntry) interface{} { return e.Total }
+	paid := func(e types.TPManifestEntry) interface{} { return e.Paid }
+	finTxn := func(e types.TPManifestEntry) interface{} { return e.FinTransactionID }
+
+	switch vMongodb.Modelling {
+	case modelEmbedded, modelUpsert:
+		colName := vMongodb.Basketcollection
+		if vMongodb.Modelling == modelEmbedded {
+			colName = vMongodb.Salescollection
+		}
+
+		return []tpCheck{{
+			name: colName,
+			col:  appLabDatabase.Collection(colName),
+			fields: map[string]func(e types.TPManifestEntry) interface{}{
+				"total":                    total,
+				"payment.paid":             paid,
+				"payment.finTransactionID": finTxn,
+			},
+		}}
+
+	default:
+		return []tpCheck{{
+			name:   vMongodb.Basketcollection,
+			col:    appLabDatabase.Collection(vMongodb.Basketcollection),
+			fields: map[string]func(e types.TPManifestEntry) interface{}{"total": total},
+		}, {
+			name: vMongodb.Paymentcollection,
+			col:  appLabDatabase.Collection(vMongodb.Paymentcollection),
+			fields: map[string]func(e types.TPManifestEntry) interface{}{
+				"paid":             paid,
+				"finTransactionID": finTxn,
+			},
+		}}
+	}
+}
+
+// Dotted path lookup into a decoded document, ie payment.paid
+func docValue(doc bson.M, path string) interface{} {
+
+	var v interface{} = doc
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(bson.M)
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+// Numbers can come back as double, int32 or int64 depending on how they were written, JSON inserts of 10.0 arrive as int32.
+// A zero is left out of the document altogether, omitempty, so a missing number is a 0.
+func sameValue(expected interface{}, got interface{}) bool {
+
+	if e, ok := expected.(float64); ok {
+		var g float64
+		switch n := got.(type) {
+		case nil:
+			g = 0
+		case float64:
+			g = n
+		case int32:
+			g = float64(n)
+		case int64:
+			g = float64(n)
+		default:
+			return false
+		}
+		return math.Abs(e-g) < 0.005
+	}
+
+	return fmt.Sprint(expected) == fmt.Sprint(got)
+}
+
+func (c *tpCheck) reconcile(ctx context.Context, entries []types.TPManifestEntry) (tpVerifyResult, error) {
+
+	result := tpVerifyResult{name: c.name, expected: len(entries)}
+
+	projection := bson.M{"invoiceNumber": 1}
+	for field := range c.fields {
+		projection[field] = 1
+	}
+
+	for start := 0; start < len(entries); start += verifyChunk {
+		end := start + verifyChunk
+		if end > len(entries) {
+			end = len(entries)
+		}
+
+		var invoices []string
+		for _, e := range entries[start:end] {
+			invoices = append(invoices, e.InvoiceNumber)
+		}
+
+		cursor, err := c.col.Find(ctx, bson.M{"invoiceNumber": bson.M{"$in": invoices}}, options.Find().SetProjection(projection))
+		if err != nil {
+			return result, err
+		}
+
+		var docs []bson.M
+		if err = cursor.All(ctx, &docs); err != nil {
+			return result, err
+		}
+
+		byInvoice := make(map[string][]bson.M)
+		for _, doc := range docs {
+			invoice := fmt.Sprint(doc["invoiceNumber"])
+			byInvoice[invoice] = append(byInvoice[invoice], doc)
+		}
+
+		for _, e := range entries[start:end] {
+			found := byInvoice[e.InvoiceNumber]
+
+			switch {
+			case len(found) == 0 && e.Fault != "":
+				result.faulted = append(result.faulted, fmt.Sprintf("%s %s on the %s, missing", e.InvoiceNumber, e.Fault, e.FaultDoc))
+				continue
+
+			case len(found) == 0:
+				result.missing = append(result.missing, e.InvoiceNumber)
+				result.pending = append(result.pending, e.InvoiceNumber)
+				continue
+
+			case len(found) > 1:
+				result.duplicated = append(result.duplicated, fmt.Sprintf("%s (x%d)", e.InvoiceNumber, len(found)))
+
+			}
+			result.found++
+
+			mismatched := false
+			for field, expected := range c.fields {
+				got := docValue(found[0], field)
+				if sameValue(expected(e), got) {
+					continue
+				}
+
+				if e.Fault != "" {
+					result.faulted = append(result.faulted, fmt.Sprintf("%s %s on the %s, %s expected %v got %v", e.InvoiceNumber, e.Fault, e.FaultDoc, field, expected(e), got))
+
+				} else {
+					result.mismatched = append(result.mismatched, fmt.Sprintf("%s %s expected %v got %v", e.InvoiceNumber, field, expected(e), got))
+					mismatched = true
+
+				}
+			}
+			if mismatched {
+				result.pending = append(result.pending, e.InvoiceNumber)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Wait on the Datastore change stream for the pending documents to arrive, or the deadline to pass.
+// pending is keyed "<collection>/<invoiceNumber>". Change streams needs a replica set, on a standalone
+// server we simply wait out the deadline.
+func waitForPending(appLabDatabase *mongo.Database, pending map[string]bool, wait time.Duration) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), wait)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{bson.D{{Key: "$match", Value: bson.M{
+		"operationType": bson.M{"$in": bson.A{"insert", "update", "replace"}},
+	}}}}
+
+	stream, err := appLabDatabase.Watch(ctx, pipeline, options.ChangeStream().SetFullDocument(options.UpdateLookup))
+	if err != nil {
+		grpcLog.Warningln("* Change stream not available, waiting out Verify_wait instead: ", err)
+		<-ctx.Done()
+		return
+
+	}
+	defer stream.Close(context.TODO())
+
+	grpcLog.Infoln(fmt.Sprintf("* Watching %s for %d pending documents, up to %s", vMongodb.Datastore, len(pending), wait))
+
+	for len(pending) > 0 && stream.Next(ctx) {
+		colName, _ := stream.Current.Lookup("ns", "coll").StringValueOK()
+		invoice, _ := stream.Current.Lookup("fullDocument", "invoiceNumber").StringValueOK()
+
+		key := colName + "/" + invoice
+		if pending[key] {
+			delete(pending, key)
+
+			if vGeneral.Debuglevel > 1 {
+				grpcLog.Infoln("* Arrived                     :", key)
+
+			}
+		}
+	}
+}
+
+func printVerifyResult(result tpVerifyResult) {
+
+	grpcLog.Infoln("")
+	grpcLog.Infoln("****** Collection", result.name, "*****")
+	grpcLog.Infoln("* Expected                    :", result.expected)
+	grpcLog.Infoln("* Found                       :", result.found)
+	grpcLog.Infoln("* Missing                     :", len(result.missing))
+	grpcLog.Infoln("* Duplicated                  :", len(result.duplicated))
+	grpcLog.Infoln("* Mismatched                  :", len(result.mismatched))
+	grpcLog.Infoln("* Faulted, as expected        :", len(result.faulted))
+
+	for _, invoice := range result.missing {
+		grpcLog.Infoln("*   missing    ", invoice)
+	}
+	for _, invoice := range result.duplicated {
+		grpcLog.Infoln("*   duplicated ", invoice)
+	}
+	for _, mismatch := range result.mismatched {
+		grpcLog.Infoln("*   mismatched ", mismatch)
+	}
+	for _, fault := range result.faulted {
+		grpcLog.Infoln("*   faulted    ", fault)
+	}
+	grpcLog.Infoln("*")
+
+}
+
+// Reconcile the run manifest against the Mongo Datastore, exits 1 if anything is missing, duplicated or mismatched.
+func runVerify(opts *tpOptions) {
+
+	loadSettings(opts, false, true)
+
+	runId = opts.runId
+	if runId == "" {
+		grpcLog.Fatalln("verify: --run-id is required")
+
+	}
+
+	fileName := manifestFileName(runId)
+	entries, err := loadManifest(fileName)
+	if err != nil {
+		grpcLog.Fatalln("Error Reading Manifest File: ", err)
+
+	}
+
+	grpcLog.Infoln("*")
+	grpcLog.Infoln("* Verify Run Id               :", runId)
+	grpcLog.Infoln("* Manifest File               :", fileName)
+	grpcLog.Infoln("* Invoices in Manifest        :", len(entries))
+	grpcLog.Infoln("* Modelling                   :", vMongodb.Modelling)
+	grpcLog.Infoln("*")
+
+	Mongoclient, err := connectMongo(vMongodb)
+	if err != nil {
+		grpcLog.Fatal("Mongo Connect Failed: ", err)
+	}
+	defer Mongoclient.Disconnect(context.TODO())
+
+	appLabDatabase := Mongoclient.Database(vMongodb.Datastore)
+	checks := verifyChecks(appLabDatabase)
+
+	reconcileAll := func() []tpVerifyResult {
+		var results []tpVerifyResult
+		for _, c := range checks {
+			result, err := c.reconcile(context.TODO(), entries)
+			if err != nil {
+				grpcLog.Fatalln("Reconcile of", c.name, "failed: ", err)
+
+			}
+			results = append(results, result)
+		}
+		return results
+	}
+
+	results := reconcileAll()
+
+	pending := make(map[string]bool)
+	for _, result := range results {
+		for _, invoice := range result.pending {
+			pending[result.name+"/"+invoice] = true
+		}
+	}
+
+	if len(pending) > 0 && vMongodb.Verify_wait > 0 {
+		waitForPending(appLabDatabase, pending, time.Duration(vMongodb.Verify_wait)*time.Second)
+		results = reconcileAll()
+
+	}
+
+	failed := false
+	for _, result := range results {
+		printVerifyResult(result)
+		if len(result.missing)+len(result.duplicated)+len(result.mismatched) > 0 {
+			failed = true
+		}
+	}
+
+	if failed {
+		grpcLog.Infoln("**** VERIFY FAILED ****")
+		grpcLog.Infoln("")
+		Mongoclient.Disconnect(context.TODO())
+		os.Exit(1)
+
+	}
+
+	grpcLog.Infoln("**** VERIFY OK ****")
+	grpcLog.Infoln("")
+
+}
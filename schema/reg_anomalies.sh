@@ -0,0 +1,7 @@
+#!/bin/bash
+
+schema=$(cat schema_anomalies.json | sed 's/\"/\\\"/g' | tr -d "\n\r")
+SCHEMA="{\"schema\": \"$schema\", \"schemaType\": \"PROTOBUF\"}"
+curl -X POST -H "Content-Type: application/vnd.schemaregistry.v1+json" \
+  --data "$SCHEMA" \
+  http://localhost:8081/subjects/pb_anomalies-value/versions
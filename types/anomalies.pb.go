@@ -0,0 +1,233 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.1
+// 	protoc        v5.26.1
+// source: anomalies.proto
+
+package types
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PBAnomalyLabel struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LabelId          string `protobuf:"bytes,1,opt,name=labelId,proto3" json:"labelId,omitempty"`
+	InvoiceNumber    string `protobuf:"bytes,2,opt,name=invoiceNumber,proto3" json:"invoiceNumber,omitempty"`
+	AnomalyType      string `protobuf:"bytes,3,opt,name=anomalyType,proto3" json:"anomalyType,omitempty"`
+	Detail           string `protobuf:"bytes,4,opt,name=detail,proto3" json:"detail,omitempty"`
+	StoreId          string `protobuf:"bytes,5,opt,name=storeId,proto3" json:"storeId,omitempty"`
+	ClerkId          string `protobuf:"bytes,6,opt,name=clerkId,proto3" json:"clerkId,omitempty"`
+	TerminalPoint    string `protobuf:"bytes,7,opt,name=terminalPoint,proto3" json:"terminalPoint,omitempty"`
+	SaleDateTime     string `protobuf:"bytes,8,opt,name=saleDateTime,proto3" json:"saleDateTime,omitempty"`
+	SaleTimestamp    string `protobuf:"bytes,9,opt,name=saleTimestamp,proto3" json:"saleTimestamp,omitempty"`
+	ProduceTimestamp int64  `protobuf:"varint,10,opt,name=produceTimestamp,proto3" json:"produceTimestamp,omitempty"`
+}
+
+func (x *PBAnomalyLabel) Reset() {
+	*x = PBAnomalyLabel{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_anomalies_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PBAnomalyLabel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PBAnomalyLabel) ProtoMessage() {}
+
+func (x *PBAnomalyLabel) ProtoReflect() protoreflect.Message {
+	mi := &file_anomalies_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PBAnomalyLabel.ProtoReflect.Descriptor instead.
+func (*PBAnomalyLabel) Descriptor() ([]byte, []int) {
+	return file_anomalies_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PBAnomalyLabel) GetLabelId() string {
+	if x != nil {
+		return x.LabelId
+	}
+	return ""
+}
+
+func (x *PBAnomalyLabel) GetInvoiceNumber() string {
+	if x != nil {
+		return x.InvoiceNumber
+	}
+	return ""
+}
+
+func (x *PBAnomalyLabel) GetAnomalyType() string {
+	if x != nil {
+		return x.AnomalyType
+	}
+	return ""
+}
+
+func (x *PBAnomalyLabel) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *PBAnomalyLabel) GetStoreId() string {
+	if x != nil {
+		return x.StoreId
+	}
+	return ""
+}
+
+func (x *PBAnomalyLabel) GetClerkId() string {
+	if x != nil {
+		return x.ClerkId
+	}
+	return ""
+}
+
+func (x *PBAnomalyLabel) GetTerminalPoint() string {
+	if x != nil {
+		return x.TerminalPoint
+	}
+	return ""
+}
+
+func (x *PBAnomalyLabel) GetSaleDateTime() string {
+	if x != nil {
+		return x.SaleDateTime
+	}
+	return ""
+}
+
+func (x *PBAnomalyLabel) GetSaleTimestamp() string {
+	if x != nil {
+		return x.SaleTimestamp
+	}
+	return ""
+}
+
+func (x *PBAnomalyLabel) GetProduceTimestamp() int64 {
+	if x != nil {
+		return x.ProduceTimestamp
+	}
+	return 0
+}
+
+var File_anomalies_proto protoreflect.FileDescriptor
+
+var file_anomalies_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x61, 0x6e, 0x6f, 0x6d, 0x61, 0x6c, 0x69, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x22, 0xda, 0x02, 0x0a, 0x0e, 0x50, 0x42, 0x41,
+	0x6e, 0x6f, 0x6d, 0x61, 0x6c, 0x79, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x6c,
+	0x61, 0x62, 0x65, 0x6c, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6c, 0x61,
+	0x62, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x24, 0x0a, 0x0d, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65,
+	0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x69, 0x6e,
+	0x76, 0x6f, 0x69, 0x63, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x61,
+	0x6e, 0x6f, 0x6d, 0x61, 0x6c, 0x79, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x61, 0x6e, 0x6f, 0x6d, 0x61, 0x6c, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64,
+	0x65, 0x74, 0x61, 0x69, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x49, 0x64,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x49, 0x64, 0x12,
+	0x18, 0x0a, 0x07, 0x63, 0x6c, 0x65, 0x72, 0x6b, 0x49, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x63, 0x6c, 0x65, 0x72, 0x6b, 0x49, 0x64, 0x12, 0x24, 0x0a, 0x0d, 0x74, 0x65, 0x72,
+	0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12,
+	0x22, 0x0a, 0x0c, 0x73, 0x61, 0x6c, 0x65, 0x44, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x61, 0x6c, 0x65, 0x44, 0x61, 0x74, 0x65, 0x54,
+	0x69, 0x6d, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x73, 0x61, 0x6c, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x61, 0x6c, 0x65,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x2a, 0x0a, 0x10, 0x70, 0x72, 0x6f,
+	0x64, 0x75, 0x63, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x10, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x42, 0x03, 0x5a, 0x01, 0x2e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_anomalies_proto_rawDescOnce sync.Once
+	file_anomalies_proto_rawDescData = file_anomalies_proto_rawDesc
+)
+
+func file_anomalies_proto_rawDescGZIP() []byte {
+	file_anomalies_proto_rawDescOnce.Do(func() {
+		file_anomalies_proto_rawDescData = protoimpl.X.CompressGZIP(file_anomalies_proto_rawDescData)
+	})
+	return file_anomalies_proto_rawDescData
+}
+
+var file_anomalies_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_anomalies_proto_goTypes = []interface{}{
+	(*PBAnomalyLabel)(nil), // 0: types.PBAnomalyLabel
+}
+var file_anomalies_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_anomalies_proto_init() }
+func file_anomalies_proto_init() {
+	if File_anomalies_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_anomalies_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PBAnomalyLabel); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_anomalies_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_anomalies_proto_goTypes,
+		DependencyIndexes: file_anomalies_proto_depIdxs,
+		MessageInfos:      file_anomalies_proto_msgTypes,
+	}.Build()
+	File_anomalies_proto = out.File
+	file_anomalies_proto_rawDesc = nil
+	file_anomalies_proto_goTypes = nil
+	file_anomalies_proto_depIdxs = nil
+}
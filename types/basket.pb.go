@@ -25,12 +25,17 @@ type BasketItem struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id       string  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name     string  `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Brand    string  `protobuf:"bytes,3,opt,name=brand,proto3" json:"brand,omitempty"`
-	Category string  `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
-	Price    float64 `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
-	Quantity int32   `protobuf:"varint,6,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Id          string  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string  `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Brand       string  `protobuf:"bytes,3,opt,name=brand,proto3" json:"brand,omitempty"`
+	Category    string  `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
+	Price       float64 `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity    int32   `protobuf:"varint,6,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Discount    float64 `protobuf:"fixed64,7,opt,name=discount,proto3" json:"discount,omitempty"`
+	PromotionId string  `protobuf:"bytes,8,opt,name=promotionId,proto3" json:"promotionId,omitempty"`
+	TaxCategory string  `protobuf:"bytes,9,opt,name=taxCategory,proto3" json:"taxCategory,omitempty"`
+	TaxRate     float64 `protobuf:"fixed64,10,opt,name=taxRate,proto3" json:"taxRate,omitempty"`
+	Tax         float64 `protobuf:"fixed64,11,opt,name=tax,proto3" json:"tax,omitempty"`
 }
 
 func (x *BasketItem) Reset() {
@@ -107,6 +112,41 @@ func (x *BasketItem) GetQuantity() int32 {
 	return 0
 }
 
+func (x *BasketItem) GetDiscount() float64 {
+	if x != nil {
+		return x.Discount
+	}
+	return 0
+}
+
+func (x *BasketItem) GetPromotionId() string {
+	if x != nil {
+		return x.PromotionId
+	}
+	return ""
+}
+
+func (x *BasketItem) GetTaxCategory() string {
+	if x != nil {
+		return x.TaxCategory
+	}
+	return ""
+}
+
+func (x *BasketItem) GetTaxRate() float64 {
+	if x != nil {
+		return x.TaxRate
+	}
+	return 0
+}
+
+func (x *BasketItem) GetTax() float64 {
+	if x != nil {
+		return x.Tax
+	}
+	return 0
+}
+
 type Idstruct struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -162,27 +202,201 @@ func (x *Idstruct) GetName() string {
 	return ""
 }
 
+type Customer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id          string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	LoyaltyTier string `protobuf:"bytes,3,opt,name=loyaltyTier,proto3" json:"loyaltyTier,omitempty"`
+	HomeStoreId string `protobuf:"bytes,4,opt,name=homeStoreId,proto3" json:"homeStoreId,omitempty"`
+	Email       string `protobuf:"bytes,5,opt,name=email,proto3" json:"email,omitempty"`
+	Phone       string `protobuf:"bytes,6,opt,name=phone,proto3" json:"phone,omitempty"`
+}
+
+func (x *Customer) Reset() {
+	*x = Customer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_basket_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Customer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Customer) ProtoMessage() {}
+
+func (x *Customer) ProtoReflect() protoreflect.Message {
+	mi := &file_basket_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Customer.ProtoReflect.Descriptor instead.
+func (*Customer) Descriptor() ([]byte, []int) {
+	return file_basket_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Customer) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Customer) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Customer) GetLoyaltyTier() string {
+	if x != nil {
+		return x.LoyaltyTier
+	}
+	return ""
+}
+
+func (x *Customer) GetHomeStoreId() string {
+	if x != nil {
+		return x.HomeStoreId
+	}
+	return ""
+}
+
+func (x *Customer) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Customer) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+type Address struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Recipient  string `protobuf:"bytes,1,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Street     string `protobuf:"bytes,2,opt,name=street,proto3" json:"street,omitempty"`
+	City       string `protobuf:"bytes,3,opt,name=city,proto3" json:"city,omitempty"`
+	PostalCode string `protobuf:"bytes,4,opt,name=postalCode,proto3" json:"postalCode,omitempty"`
+	Region     string `protobuf:"bytes,5,opt,name=region,proto3" json:"region,omitempty"`
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_basket_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_basket_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_basket_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Address) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *Address) GetStreet() string {
+	if x != nil {
+		return x.Street
+	}
+	return ""
+}
+
+func (x *Address) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Address) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *Address) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
 type PBBasket struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	InvoiceNumber string        `protobuf:"bytes,1,opt,name=invoiceNumber,proto3" json:"invoiceNumber,omitempty"`
-	SaleDateTime  string        `protobuf:"bytes,2,opt,name=saleDateTime,proto3" json:"saleDateTime,omitempty"`
-	SaleTimestamp string        `protobuf:"bytes,3,opt,name=saleTimestamp,proto3" json:"saleTimestamp,omitempty"`
-	Store         *Idstruct     `protobuf:"bytes,4,opt,name=store,proto3" json:"store,omitempty"`
-	Clerk         *Idstruct     `protobuf:"bytes,5,opt,name=clerk,proto3" json:"clerk,omitempty"`
-	TerminalPoint string        `protobuf:"bytes,6,opt,name=terminalPoint,proto3" json:"terminalPoint,omitempty"`
-	BasketItems   []*BasketItem `protobuf:"bytes,7,rep,name=basketItems,proto3" json:"basketItems,omitempty"`
-	Nett          float64       `protobuf:"fixed64,8,opt,name=nett,proto3" json:"nett,omitempty"`
-	Vat           float64       `protobuf:"fixed64,9,opt,name=vat,proto3" json:"vat,omitempty"`
-	Total         float64       `protobuf:"fixed64,10,opt,name=total,proto3" json:"total,omitempty"`
+	InvoiceNumber    string        `protobuf:"bytes,1,opt,name=invoiceNumber,proto3" json:"invoiceNumber,omitempty"`
+	SaleDateTime     string        `protobuf:"bytes,2,opt,name=saleDateTime,proto3" json:"saleDateTime,omitempty"`
+	SaleTimestamp    string        `protobuf:"bytes,3,opt,name=saleTimestamp,proto3" json:"saleTimestamp,omitempty"`
+	Store            *Idstruct     `protobuf:"bytes,4,opt,name=store,proto3" json:"store,omitempty"`
+	Clerk            *Idstruct     `protobuf:"bytes,5,opt,name=clerk,proto3" json:"clerk,omitempty"`
+	TerminalPoint    string        `protobuf:"bytes,6,opt,name=terminalPoint,proto3" json:"terminalPoint,omitempty"`
+	BasketItems      []*BasketItem `protobuf:"bytes,7,rep,name=basketItems,proto3" json:"basketItems,omitempty"`
+	Nett             float64       `protobuf:"fixed64,8,opt,name=nett,proto3" json:"nett,omitempty"`
+	Vat              float64       `protobuf:"fixed64,9,opt,name=vat,proto3" json:"vat,omitempty"`
+	Total            float64       `protobuf:"fixed64,10,opt,name=total,proto3" json:"total,omitempty"`
+	ProduceTimestamp int64         `protobuf:"varint,11,opt,name=produceTimestamp,proto3" json:"produceTimestamp,omitempty"`
+	TerminalType     string        `protobuf:"bytes,12,opt,name=terminalType,proto3" json:"terminalType,omitempty"`
+	Customer         *Customer     `protobuf:"bytes,13,opt,name=customer,proto3" json:"customer,omitempty"`
+	Discount         float64       `protobuf:"fixed64,14,opt,name=discount,proto3" json:"discount,omitempty"`
+	Currency         string        `protobuf:"bytes,15,opt,name=currency,proto3" json:"currency,omitempty"`
+	Delivery         *Address      `protobuf:"bytes,16,opt,name=delivery,proto3" json:"delivery,omitempty"`
+	ShippingFee      float64       `protobuf:"fixed64,17,opt,name=shippingFee,proto3" json:"shippingFee,omitempty"`
+	TransactionType  string        `protobuf:"bytes,18,opt,name=transactionType,proto3" json:"transactionType,omitempty"`
 }
 
 func (x *PBBasket) Reset() {
 	*x = PBBasket{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_basket_proto_msgTypes[2]
+		mi := &file_basket_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -195,7 +409,7 @@ func (x *PBBasket) String() string {
 func (*PBBasket) ProtoMessage() {}
 
 func (x *PBBasket) ProtoReflect() protoreflect.Message {
-	mi := &file_basket_proto_msgTypes[2]
+	mi := &file_basket_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -208,7 +422,7 @@ func (x *PBBasket) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PBBasket.ProtoReflect.Descriptor instead.
 func (*PBBasket) Descriptor() ([]byte, []int) {
-	return file_basket_proto_rawDescGZIP(), []int{2}
+	return file_basket_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *PBBasket) GetInvoiceNumber() string {
@@ -281,11 +495,67 @@ func (x *PBBasket) GetTotal() float64 {
 	return 0
 }
 
+func (x *PBBasket) GetProduceTimestamp() int64 {
+	if x != nil {
+		return x.ProduceTimestamp
+	}
+	return 0
+}
+
+func (x *PBBasket) GetTerminalType() string {
+	if x != nil {
+		return x.TerminalType
+	}
+	return ""
+}
+
+func (x *PBBasket) GetCustomer() *Customer {
+	if x != nil {
+		return x.Customer
+	}
+	return nil
+}
+
+func (x *PBBasket) GetDiscount() float64 {
+	if x != nil {
+		return x.Discount
+	}
+	return 0
+}
+
+func (x *PBBasket) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PBBasket) GetDelivery() *Address {
+	if x != nil {
+		return x.Delivery
+	}
+	return nil
+}
+
+func (x *PBBasket) GetShippingFee() float64 {
+	if x != nil {
+		return x.ShippingFee
+	}
+	return 0
+}
+
+func (x *PBBasket) GetTransactionType() string {
+	if x != nil {
+		return x.TransactionType
+	}
+	return ""
+}
+
 var File_basket_proto protoreflect.FileDescriptor
 
 var file_basket_proto_rawDesc = []byte{
 	0x0a, 0x0c, 0x62, 0x61, 0x73, 0x6b, 0x65, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05,
-	0x74, 0x79, 0x70, 0x65, 0x73, 0x22, 0x94, 0x01, 0x0a, 0x0a, 0x42, 0x61, 0x73, 0x6b, 0x65, 0x74,
+	0x74, 0x79, 0x70, 0x65, 0x73, 0x22, 0xa0, 0x02, 0x0a, 0x0a, 0x42, 0x61, 0x73, 0x6b, 0x65, 0x74,
 	0x49, 0x74, 0x65, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
 	0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
 	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x72, 0x61, 0x6e,
@@ -294,33 +564,80 @@ var file_basket_proto_rawDesc = []byte{
 	0x52, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72,
 	0x69, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65,
 	0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x06, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x22, 0x2e, 0x0a, 0x08,
-	0x49, 0x64, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0xdf, 0x02, 0x0a,
-	0x08, 0x50, 0x42, 0x42, 0x61, 0x73, 0x6b, 0x65, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x69, 0x6e, 0x76,
-	0x6f, 0x69, 0x63, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0d, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12,
-	0x22, 0x0a, 0x0c, 0x73, 0x61, 0x6c, 0x65, 0x44, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x61, 0x6c, 0x65, 0x44, 0x61, 0x74, 0x65, 0x54,
-	0x69, 0x6d, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x73, 0x61, 0x6c, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73,
-	0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x61, 0x6c, 0x65,
-	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x25, 0x0a, 0x05, 0x73, 0x74, 0x6f,
-	0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73,
-	0x2e, 0x49, 0x64, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65,
-	0x12, 0x25, 0x0a, 0x05, 0x63, 0x6c, 0x65, 0x72, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x0f, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x49, 0x64, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74,
-	0x52, 0x05, 0x63, 0x6c, 0x65, 0x72, 0x6b, 0x12, 0x24, 0x0a, 0x0d, 0x74, 0x65, 0x72, 0x6d, 0x69,
-	0x6e, 0x61, 0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d,
-	0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x33, 0x0a,
-	0x0b, 0x62, 0x61, 0x73, 0x6b, 0x65, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x07, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x42, 0x61, 0x73, 0x6b, 0x65,
-	0x74, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x0b, 0x62, 0x61, 0x73, 0x6b, 0x65, 0x74, 0x49, 0x74, 0x65,
-	0x6d, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x65, 0x74, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01,
-	0x52, 0x04, 0x6e, 0x65, 0x74, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x76, 0x61, 0x74, 0x18, 0x09, 0x20,
-	0x01, 0x28, 0x01, 0x52, 0x03, 0x76, 0x61, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61,
-	0x6c, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x03,
-	0x5a, 0x01, 0x2e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x28, 0x05, 0x52, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a, 0x08,
+	0x64, 0x69, 0x73, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08,
+	0x64, 0x69, 0x73, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x6d,
+	0x6f, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70,
+	0x72, 0x6f, 0x6d, 0x6f, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x74, 0x61,
+	0x78, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x74, 0x61, 0x78, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x18, 0x0a, 0x07,
+	0x74, 0x61, 0x78, 0x52, 0x61, 0x74, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x74,
+	0x61, 0x78, 0x52, 0x61, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x78, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x03, 0x74, 0x61, 0x78, 0x22, 0x2e, 0x0a, 0x08, 0x49, 0x64, 0x73, 0x74,
+	0x72, 0x75, 0x63, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x9e, 0x01, 0x0a, 0x08, 0x43, 0x75, 0x73,
+	0x74, 0x6f, 0x6d, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x6c, 0x6f, 0x79,
+	0x61, 0x6c, 0x74, 0x79, 0x54, 0x69, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x6c, 0x6f, 0x79, 0x61, 0x6c, 0x74, 0x79, 0x54, 0x69, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x68,
+	0x6f, 0x6d, 0x65, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x68, 0x6f, 0x6d, 0x65, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x49, 0x64, 0x12, 0x14, 0x0a,
+	0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d,
+	0x61, 0x69, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x22, 0x8b, 0x01, 0x0a, 0x07, 0x41, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69,
+	0x65, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x72, 0x65, 0x65, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x72, 0x65, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x63,
+	0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x74, 0x79, 0x12,
+	0x1e, 0x0a, 0x0a, 0x70, 0x6f, 0x73, 0x74, 0x61, 0x6c, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x6f, 0x73, 0x74, 0x61, 0x6c, 0x43, 0x6f, 0x64, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x22, 0x8c, 0x05, 0x0a, 0x08, 0x50, 0x42, 0x42, 0x61,
+	0x73, 0x6b, 0x65, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x4e,
+	0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x69, 0x6e, 0x76,
+	0x6f, 0x69, 0x63, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x61,
+	0x6c, 0x65, 0x44, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x73, 0x61, 0x6c, 0x65, 0x44, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x24,
+	0x0a, 0x0d, 0x73, 0x61, 0x6c, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x61, 0x6c, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x12, 0x25, 0x0a, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x49, 0x64, 0x73, 0x74,
+	0x72, 0x75, 0x63, 0x74, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x25, 0x0a, 0x05, 0x63,
+	0x6c, 0x65, 0x72, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x74, 0x79, 0x70,
+	0x65, 0x73, 0x2e, 0x49, 0x64, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x05, 0x63, 0x6c, 0x65,
+	0x72, 0x6b, 0x12, 0x24, 0x0a, 0x0d, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x50, 0x6f,
+	0x69, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x65, 0x72, 0x6d, 0x69,
+	0x6e, 0x61, 0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x33, 0x0a, 0x0b, 0x62, 0x61, 0x73, 0x6b,
+	0x65, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x42, 0x61, 0x73, 0x6b, 0x65, 0x74, 0x49, 0x74, 0x65, 0x6d,
+	0x52, 0x0b, 0x62, 0x61, 0x73, 0x6b, 0x65, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x65, 0x74, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x6e, 0x65, 0x74,
+	0x74, 0x12, 0x10, 0x0a, 0x03, 0x76, 0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03,
+	0x76, 0x61, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x2a, 0x0a, 0x10, 0x70, 0x72, 0x6f,
+	0x64, 0x75, 0x63, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x10, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x22, 0x0a, 0x0c, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61,
+	0x6c, 0x54, 0x79, 0x70, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x74, 0x65, 0x72,
+	0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2b, 0x0a, 0x08, 0x63, 0x75, 0x73,
+	0x74, 0x6f, 0x6d, 0x65, 0x72, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x74, 0x79,
+	0x70, 0x65, 0x73, 0x2e, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x65, 0x72, 0x52, 0x08, 0x63, 0x75,
+	0x73, 0x74, 0x6f, 0x6d, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x0f,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x2a,
+	0x0a, 0x08, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x52, 0x08, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x68,
+	0x69, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x46, 0x65, 0x65, 0x18, 0x11, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0b, 0x73, 0x68, 0x69, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x46, 0x65, 0x65, 0x12, 0x28, 0x0a, 0x0f,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x18,
+	0x12, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x42, 0x03, 0x5a, 0x01, 0x2e, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -335,21 +652,25 @@ func file_basket_proto_rawDescGZIP() []byte {
 	return file_basket_proto_rawDescData
 }
 
-var file_basket_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_basket_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_basket_proto_goTypes = []interface{}{
 	(*BasketItem)(nil), // 0: types.BasketItem
 	(*Idstruct)(nil),   // 1: types.Idstruct
-	(*PBBasket)(nil),   // 2: types.PBBasket
+	(*Customer)(nil),   // 2: types.Customer
+	(*Address)(nil),    // 3: types.Address
+	(*PBBasket)(nil),   // 4: types.PBBasket
 }
 var file_basket_proto_depIdxs = []int32{
 	1, // 0: types.PBBasket.store:type_name -> types.Idstruct
 	1, // 1: types.PBBasket.clerk:type_name -> types.Idstruct
 	0, // 2: types.PBBasket.basketItems:type_name -> types.BasketItem
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	2, // 3: types.PBBasket.customer:type_name -> types.Customer
+	3, // 4: types.PBBasket.delivery:type_name -> types.Address
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_basket_proto_init() }
@@ -383,6 +704,30 @@ func file_basket_proto_init() {
 			}
 		}
 		file_basket_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Customer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_basket_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Address); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_basket_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*PBBasket); i {
 			case 0:
 				return &v.state
@@ -401,7 +746,7 @@ func file_basket_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_basket_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
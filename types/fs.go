@@ -1,5 +1,12 @@
 package types
 
+// The unified <env>.yaml|.toml|.json configuration file, one section per legacy *_app/_kafka/_mongo.json file
+type TPConfig struct {
+	App   TPGeneral `json:"app"`
+	Kafka TPKafka   `json:"kafka"`
+	Mongo TPMongodb `json:"mongo"`
+}
+
 // Structs - the values we bring in from *app.json configuration file
 type TPGeneral struct {
 	EchoConfig        int
@@ -7,76 +14,320 @@ type TPGeneral struct {
 	Debuglevel        int
 	Testsize          int     // Used to limit number of records posted, over rided when reading test cases from input_source,
 	Sleep             int     // sleep time between Basket payload Create and Payment payload create
+	Rate              float64 // if > 0 then produce at this many records/second, rather than the random Sleep
 	SeedFile          string  // Which seed file to read in
+	Seed_source       string  // where stores, clerks and products come from, file (default), mongo, postgres or csv
+	Seed_location     string  // postgres connection string / csv directory, relative to --config-dir
+	Seed_prefix       string  // prefix of the seed collections/tables/files, ie seed_ => seed_stores, seed_clerks...
+	Seed_refresh      int     // re-read the seed every so many seconds during a run, 0 => never
 	EchoSeed          int     // 0/1 Echo the seed data to terminal
 	CurrentPath       string  // current
 	OSName            string  // OS name
-	Vatrate           float64 // Amount
+	Vatrate           float64 // Amount, the standard tax rate unless the seed tax rates say otherwise
+	Tax_inclusive     int     // if = 1 then the seed prices include tax, as in South African retail
+	Currency          string  // ISO 4217 code of the seed prices and of stores without a seed currency, ie ZAR
+	Foreign_cards     float64 // share of the card payments made with a card in another seed currency
 	Store             int     // if <> 0 then store at that position in array is selected.
-	Terminals         int     // Number of possible checkout points/terminals
+	Terminals         int     // Number of tills for stores without seed terminals, 0 => 20
 	KafkaEnabled      int     // if = 1 then post docs to kafka
 	MongoAtlasEnabled int     // if = 1 then post docs to MongoDB
 	Json_to_file      int     // do we spool the created baskets and payments to a file/s
 	Output_path       string  // if yes above then pipe json here. we will spool the baskets to one file and the payments to a second.
-	TimeOffset        string  // what offset do we run with, from GMT / Zulu time
+	Manifest          int     // if = 1 then record every invoice produced to <Output_path>/<runId>_manifest.json, used by verify
+	Timezone          string  // IANA zone of stores without a seed timezone, blank => the machine's local zone
+	TimestampFormat   string  // saleDateTime/payDateTime, rfc3339 (default), epoch_ms or epoch_us
 	Max_items_basket  int     // max items in a basket
 	Max_quantity      int     // max quantity of items in a basket per product
+	Customers         int     // loyalty customer pool size, 0 => no customers
+	Customer_share    float64 // share of the baskets with a loyalty customer
+	Points_rate       float64 // loyalty points earned per 1 spent, 0 => 1
+	Redeem_share      float64 // share of the customer payments that redeem the points balance
+	Distribution      string  // uniform, weighted (default), zipf or pareto, see cmd/distribution.go
+	Skew              float64 // zipf exponent / pareto top 20% share, 0 => 1.07 / 0.8
+	Latency_watch     int     // latency: seconds to watch the change stream, 0 => until Ctrl-C
+	Latency           int     // if = 1 then produce, backfill and scenario watch the change stream and report the latency in their summary
+	Prom_pushgateway  string  // host:port of the Prometheus push gateway, blank => do not push
+	Faults            float64 // share of the sales with a corrupted basket or payment, see cmd/faults.go, 0 => clean
+	Fault_kinds       string  // comma separated fault kinds, blank => all of them
+	Late_payments     float64 // share of the payments made Late_delay minutes after the sale, rather than within 6 minutes
+	Late_delay        int     // minutes, on average, a late payment is made after the sale, 0 => 60
 	KafkaConfigFile   string  // Kafka configuration file
 	MongoConfigFile   string  // Mongo configuration file
 }
 
 type TPKafka struct {
-	EchoConfig        int
-	Bootstrapservers  string
-	SchemaRegistryURL string
-	BasketTopicname   string
-	PaymentTopicname  string
-	Numpartitions     int
-	Replicationfactor int
-	Retension         string
-	Parseduration     string
-	Security_protocol string
-	Sasl_mechanisms   string
-	Sasl_username     string
-	Sasl_password     string
-	Flush_interval    int
+	EchoConfig         int
+	Bootstrapservers   string
+	SchemaRegistryURL  string
+	BasketTopicname    string
+	PaymentTopicname   string
+	InventoryTopicname string // stock movements, blank => not posted to Kafka
+	PriceTopicname     string // price changes, blank => not posted to Kafka
+	OrderTopicname     string // online order lifecycle events, blank => not posted to Kafka
+	LabelTopicname     string // anomaly ground-truth labels, blank => not posted to Kafka
+	Numpartitions      int
+	Replicationfactor  int
+	Retension          string
+	Parseduration      string
+	Security_protocol  string
+	Sasl_mechanisms    string
+	Sasl_username      string
+	Sasl_password      string
+	Flush_interval     int
 }
 
 type TPMongodb struct {
-	Url               string
-	Uri               string
-	Root              string
-	Port              string
-	Username          string
-	Password          string
-	Datastore         string
-	Basketcollection  string
-	Paymentcollection string
-	Batch_size        int
+	Url                 string
+	Uri                 string
+	Root                string
+	Port                string
+	Username            string
+	Password            string
+	Datastore           string
+	Basketcollection    string
+	Paymentcollection   string
+	Salescollection     string // Modelling = embedded, combined basket + payment documents go here
+	Inventorycollection string // stock movements, blank => not inserted into Mongo
+	Pricecollection     string // price changes, blank => not inserted into Mongo
+	Ordercollection     string // online order lifecycle events, blank => not inserted into Mongo
+	Labelcollection     string // anomaly ground-truth labels, blank => not inserted into Mongo
+	Modelling           string // separate (default), embedded or upsert, see cmd/mongo.go
+	Batch_size          int
+	Verify_wait         int // verify: seconds to watch the change stream for documents still in flight
+}
+
+// One line of the run manifest, what verify expects to find in the Mongo collections
+type TPManifestEntry struct {
+	InvoiceNumber    string  `json:"invoiceNumber"`
+	Total            float64 `json:"total"`
+	Paid             float64 `json:"paid"`
+	FinTransactionID string  `json:"finTransactionID"`
+	Fault            string  `json:"fault,omitempty"`    // the fault injected, see faults.go, blank => clean
+	FaultDoc         string  `json:"faultDoc,omitempty"` // basket or payment, the document it is on
 }
 
 // the below is used as structure of the seed file
 type TPClerkStruct struct {
-	Id      string `json:"id,omitempty"`
-	Name    string `json:"name,omitempty"`
-	StoreId string `json:"storeId,omitempty"`
+	Id      string          `json:"id,omitempty"`
+	Name    string          `json:"name,omitempty"`
+	StoreId string          `json:"storeId,omitempty"`
+	Weight  float64         `json:"weight,omitempty"` // relative share of the store's baskets, missing => 1
+	Shifts  []TPShiftStruct `json:"shifts,omitempty"` // when the clerk works, none => always
+}
+
+// A clerk's shift, days as sun..sat (none => every day), start/end as HH:MM, end before start => overnight
+type TPShiftStruct struct {
+	Days  []string `json:"days,omitempty"`
+	Start string   `json:"start,omitempty"`
+	End   string   `json:"end,omitempty"`
 }
 
 type TPStoreStruct struct {
-	Id   string `json:"id,omitempty"`
-	Name string `json:"name,omitempty"`
+	Id         string             `json:"id,omitempty"`
+	Name       string             `json:"name,omitempty"`
+	Weight     float64            `json:"weight,omitempty"`    // relative share of the traffic, missing => 1
+	Terminals  []TPTerminalStruct `json:"terminals,omitempty"` // none => app Terminals tills
+	Hours      []TPShiftStruct    `json:"hours,omitempty"`     // opening hours, none => always open
+	Timezone   string             `json:"timezone,omitempty"`  // IANA zone, ie Africa/Johannesburg, blank => app Timezone
+	Currency   string             `json:"currency,omitempty"`  // ISO 4217 code, blank => app Currency
+	Address    string             `json:"address,omitempty"`
+	City       string             `json:"city,omitempty"`
+	PostalCode string             `json:"postalCode,omitempty"`
+	Region     string             `json:"region,omitempty"`
+}
+
+// A checkout point in a store
+type TPTerminalStruct struct {
+	Id     string  `json:"id,omitempty"`
+	Type   string  `json:"type,omitempty"`   // till, self-checkout, online or a seed TerminalTypes type
+	Weight float64 `json:"weight,omitempty"` // relative share of the store's baskets, missing => 1
+}
+
+// Basket size and payment method mix per terminal type, overrides the built in till/self-checkout/online profiles
+type TPTerminalType struct {
+	Type           string             `json:"type,omitempty"`
+	MinItems       int                `json:"minItems,omitempty"`
+	MaxItems       int                `json:"maxItems,omitempty"`
+	PaymentMethods map[string]float64 `json:"paymentMethods,omitempty"` // method => relative weight
 }
 
 type TProductStruct struct {
-	Id       string  `json:"id,omitempty"`
-	Name     string  `json:"name,omitempty"`
-	Brand    string  `json:"brand,omitempty"`
-	Category string  `json:"category,omitempty"`
-	Price    float64 `json:"price,omitempty"`
+	Id          string  `json:"id,omitempty"`
+	Name        string  `json:"name,omitempty"`
+	Brand       string  `json:"brand,omitempty"`
+	Category    string  `json:"category,omitempty"`
+	Price       float64 `json:"price,omitempty"`
+	Weight      float64 `json:"weight,omitempty"`      // relative popularity, missing => 1, zipf/pareto rank by it
+	TaxCategory string  `json:"taxCategory,omitempty"` // standard, zero, exempt or a seed tax rate, blank => as per its category
 }
 
 type TPSeed struct {
-	Clerks   []TPClerkStruct  `json:"clerks,omitempty"`
-	Stores   []TPStoreStruct  `json:"stores,omitempty"`
-	Products []TProductStruct `json:"products,omitempty"`
+	Clerks        []TPClerkStruct  `json:"clerks,omitempty"`
+	Stores        []TPStoreStruct  `json:"stores,omitempty"`
+	Products      []TProductStruct `json:"products,omitempty"`
+	TerminalTypes []TPTerminalType `json:"terminalTypes,omitempty"`
+	Traffic       TPTrafficStruct  `json:"traffic,omitempty"`
+	Promotions    []TPPromotion    `json:"promotions,omitempty"`
+	Tax           TPTaxStruct      `json:"tax,omitempty"`
+	Currencies    []TPCurrency     `json:"currencies,omitempty"`
+	Inventory     TPInventory      `json:"inventory,omitempty"`
+	Pricing       TPPricing        `json:"pricing,omitempty"`
+	Online        TPOnline         `json:"online,omitempty"`
+	Anomalies     TPAnomalies      `json:"anomalies,omitempty"`
+	Profiles      []TPStoreProfile `json:"profiles,omitempty"`
+}
+
+// How a store, or group of stores, sells, see cmd/profiles.go
+type TPStoreProfile struct {
+	Name      string   `json:"name,omitempty"`
+	Stores    []string `json:"stores,omitempty"`    // store ids, a store is in one profile at most
+	Items     TPRange  `json:"items,omitempty"`     // items per basket, none => as per the terminal type
+	Quantity  TPRange  `json:"quantity,omitempty"`  // quantity per item, none => 1..app Max_quantity
+	PriceTier float64  `json:"priceTier,omitempty"` // times the product prices, 0 => 1
+	Weight    float64  `json:"weight,omitempty"`    // times the stores' share of the traffic, 0 => 1
+}
+
+type TPRange struct {
+	Min          int    `json:"min,omitempty"`
+	Max          int    `json:"max,omitempty"`
+	Distribution string `json:"distribution,omitempty"` // uniform, normal or skewed (to the min), blank => uniform
+}
+
+// A multi-phase run, see cmd/scenario.go
+type TPScenario struct {
+	Name   string            `json:"name,omitempty"`
+	Phases []TPScenarioPhase `json:"phases,omitempty"`
+}
+
+type TPScenarioPhase struct {
+	Name     string                 `json:"name,omitempty"`
+	Start    string                 `json:"start,omitempty"`    // after the scenario started, ie 5m, blank => once the phase before it is done
+	Duration string                 `json:"duration,omitempty"` // ie 10m, blank => until count
+	Count    int                    `json:"count,omitempty"`    // sales, 0 => for the duration
+	Rate     float64                `json:"rate,omitempty"`     // sales/second, 0 => as configured
+	Sinks    []string               `json:"sinks,omitempty"`    // kafka, mongo and/or file, none => as configured
+	Set      map[string]interface{} `json:"set,omitempty"`      // <section>.<key> => value, as --set, ie "app.Late_payments": 0.2
+}
+
+// Stock per store and product, see cmd/inventory.go
+type TPInventory struct {
+	Opening    int            `json:"opening,omitempty"`    // opening stock per store and product, 0 => no inventory, stock is endless
+	Levels     map[string]int `json:"levels,omitempty"`     // product id => its own opening stock
+	Deliveries []TPDelivery   `json:"deliveries,omitempty"` // deliveries top every product back up to its opening stock
+	StockOut   string         `json:"stockOut,omitempty"`   // drop (default) or substitute, when a basket item is out of stock
+}
+
+// A scheduled delivery, days as sun..sat (none => every day), at HH:MM store local time
+type TPDelivery struct {
+	Days []string `json:"days,omitempty"`
+	At   string   `json:"at,omitempty"`
+}
+
+// Anomalies injected into the baskets and payments, each labelled, see cmd/anomalies.go
+type TPAnomalies struct {
+	CardTesting     TPAnomaly `json:"cardTesting,omitempty"`     // burst small card payments on one card
+	LargeBasket     TPAnomaly `json:"largeBasket,omitempty"`     // factor times the quantities
+	RefundAbuse     TPAnomaly `json:"refundAbuse,omitempty"`     // refunds by one clerk
+	Sweethearting   TPAnomaly `json:"sweethearting,omitempty"`   // items lines rung up at a zero price
+	DuplicateFinTxn TPAnomaly `json:"duplicateFinTxn,omitempty"` // a payment reusing an earlier finTransactionID
+}
+
+type TPAnomaly struct {
+	Rate   float64 `json:"rate,omitempty"`   // share of the baskets
+	Burst  int     `json:"burst,omitempty"`  // cardTesting, payments per burst, 0 => 10
+	Factor float64 `json:"factor,omitempty"` // largeBasket, 0 => 10
+	Items  int     `json:"items,omitempty"`  // sweethearting, 0 => 2
+	Clerk  string  `json:"clerk,omitempty"`  // refundAbuse, clerk id, blank => one picked for the run
+}
+
+// Online orders, the baskets of online terminals, delivered and followed through their lifecycle, see cmd/orders.go
+type TPOnline struct {
+	ShippingFee      float64       `json:"shippingFee,omitempty"`      // per order, in the app Currency
+	FreeShippingOver float64       `json:"freeShippingOver,omitempty"` // orders of this much or more ship free, 0 => never
+	CancelRate       float64       `json:"cancelRate,omitempty"`       // share of the orders cancelled before they ship
+	Carriers         []string      `json:"carriers,omitempty"`         // none => Courier Guy, DHL, PostNet
+	Delays           TPOrderDelays `json:"delays,omitempty"`
+}
+
+// Average minutes from one step of an order to the next, the actual delay is 0.5..1.5 times it
+type TPOrderDelays struct {
+	Picked    float64 `json:"picked,omitempty"`    // after paid
+	Shipped   float64 `json:"shipped,omitempty"`   // after picked
+	Delivered float64 `json:"delivered,omitempty"` // after shipped, > 0 => online orders on
+}
+
+// Price changes during a run, see cmd/pricing.go
+type TPPricing struct {
+	Inflation float64           `json:"inflation,omitempty"` // yearly drift of every price, ie 0.06, stepped up on the 1st of every month
+	Changes   []TPPriceChange   `json:"changes,omitempty"`   // scheduled changes
+	Markdowns TPRandomPriceCuts `json:"markdowns,omitempty"` // random per product markdowns
+	PriceWars TPRandomPriceCuts `json:"priceWars,omitempty"` // random per category price wars
+	Interval  int               `json:"interval,omitempty"`  // minutes between price updates, 0 => once a day, see cmd/pricing.go
+}
+
+// A scheduled change of the products' and categories' prices, from..to dates (YYYY-MM-DD), no to => for good
+type TPPriceChange struct {
+	Name       string   `json:"name,omitempty"`
+	From       string   `json:"from,omitempty"`
+	To         string   `json:"to,omitempty"`
+	Products   []string `json:"products,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	Percent    float64  `json:"percent,omitempty"` // ie 8 => up 8%, -20 => down 20%
+}
+
+// Random price cuts, rolled every day, or pricing interval, of percent off for days, or minutes
+type TPRandomPriceCuts struct {
+	Chance     float64  `json:"chance,omitempty"`     // odds per day, or interval, per product (markdowns) or of a price war
+	Percent    float64  `json:"percent,omitempty"`    // percent off
+	Days       int      `json:"days,omitempty"`       // how long the cut lasts, 0 => 1
+	Minutes    int      `json:"minutes,omitempty"`    // how long the cut lasts, if set rather than days, for a live run
+	Categories []string `json:"categories,omitempty"` // price wars, the categories fought over, none => any
+}
+
+// A currency and its FX rate, see cmd/currency.go
+type TPCurrency struct {
+	Code         string  `json:"code,omitempty"`         // ISO 4217, ie USD
+	Rate         float64 `json:"rate,omitempty"`         // value of 1 unit in the app Currency
+	CashRounding float64 `json:"cashRounding,omitempty"` // cash payments round to this, ie 0.10, 0 => 0.01
+}
+
+// Tax rates per tax category, and the tax category per product category, see cmd/tax.go
+type TPTaxStruct struct {
+	Rates      map[string]float64 `json:"rates,omitempty"`      // tax category => rate, ie "standard": 0.15
+	Categories map[string]string  `json:"categories,omitempty"` // product category => tax category
+}
+
+// Traffic model, see cmd/traffic.go
+type TPTrafficStruct struct {
+	Hourly []float64       `json:"hourly,omitempty"` // 24 values, relative demand per hour of the day
+	Rules  []TPTrafficRule `json:"rules,omitempty"`
+}
+
+// Applies when all of its days (sun..sat), months (jan..dec) and from..to dates (YYYY-MM-DD) match
+type TPTrafficRule struct {
+	Name       string             `json:"name,omitempty"`
+	Days       []string           `json:"days,omitempty"`
+	Months     []string           `json:"months,omitempty"`
+	From       string             `json:"from,omitempty"`
+	To         string             `json:"to,omitempty"`
+	Demand     float64            `json:"demand,omitempty"`     // event rate multiplier, 0 => 1
+	Items      float64            `json:"items,omitempty"`      // basket size multiplier, 0 => 1
+	Categories map[string]float64 `json:"categories,omitempty"` // product category => odds multiplier
+}
+
+// A promotion, see cmd/promotions.go, applies from..to (YYYY-MM-DD) during its hours, at its stores, none => always/all.
+type TPPromotion struct {
+	Id         string          `json:"id,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	Type       string          `json:"type,omitempty"`       // percent, buy_x_get_y, bundle or category
+	Products   []string        `json:"products,omitempty"`   // product ids, percent/buy_x_get_y/bundle
+	Categories []string        `json:"categories,omitempty"` // product categories, category
+	Percent    float64         `json:"percent,omitempty"`    // percent off, percent/category
+	Buy        int             `json:"buy,omitempty"`        // buy_x_get_y, buy Buy get Get free
+	Get        int             `json:"get,omitempty"`
+	Price      float64         `json:"price,omitempty"` // bundle price, one of each of the products
+	Stores     []string        `json:"stores,omitempty"`
+	From       string          `json:"from,omitempty"`
+	To         string          `json:"to,omitempty"`
+	Hours      []TPShiftStruct `json:"hours,omitempty"`
 }
@@ -30,6 +30,19 @@ type PBPayment struct {
 	PayTimestamp     string  `protobuf:"bytes,3,opt,name=payTimestamp,proto3" json:"payTimestamp,omitempty"`
 	Paid             float64 `protobuf:"fixed64,4,opt,name=paid,proto3" json:"paid,omitempty"`
 	FinTransactionID string  `protobuf:"bytes,5,opt,name=finTransactionID,proto3" json:"finTransactionID,omitempty"`
+	ProduceTimestamp int64   `protobuf:"varint,6,opt,name=produceTimestamp,proto3" json:"produceTimestamp,omitempty"`
+	PaymentMethod    string  `protobuf:"bytes,7,opt,name=paymentMethod,proto3" json:"paymentMethod,omitempty"`
+	CustomerId       string  `protobuf:"bytes,8,opt,name=customerId,proto3" json:"customerId,omitempty"`
+	PointsEarned     int64   `protobuf:"varint,9,opt,name=pointsEarned,proto3" json:"pointsEarned,omitempty"`
+	PointsRedeemed   int64   `protobuf:"varint,10,opt,name=pointsRedeemed,proto3" json:"pointsRedeemed,omitempty"`
+	PointsAmount     float64 `protobuf:"fixed64,11,opt,name=pointsAmount,proto3" json:"pointsAmount,omitempty"`
+	PointsBalance    int64   `protobuf:"varint,12,opt,name=pointsBalance,proto3" json:"pointsBalance,omitempty"`
+	Currency         string  `protobuf:"bytes,13,opt,name=currency,proto3" json:"currency,omitempty"`
+	Rounding         float64 `protobuf:"fixed64,14,opt,name=rounding,proto3" json:"rounding,omitempty"`
+	CardCurrency     string  `protobuf:"bytes,15,opt,name=cardCurrency,proto3" json:"cardCurrency,omitempty"`
+	FxRate           float64 `protobuf:"fixed64,16,opt,name=fxRate,proto3" json:"fxRate,omitempty"`
+	CardAmount       float64 `protobuf:"fixed64,17,opt,name=cardAmount,proto3" json:"cardAmount,omitempty"`
+	CardNumber       string  `protobuf:"bytes,18,opt,name=cardNumber,proto3" json:"cardNumber,omitempty"`
 }
 
 func (x *PBPayment) Reset() {
@@ -99,11 +112,102 @@ func (x *PBPayment) GetFinTransactionID() string {
 	return ""
 }
 
+func (x *PBPayment) GetProduceTimestamp() int64 {
+	if x != nil {
+		return x.ProduceTimestamp
+	}
+	return 0
+}
+
+func (x *PBPayment) GetPaymentMethod() string {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return ""
+}
+
+func (x *PBPayment) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *PBPayment) GetPointsEarned() int64 {
+	if x != nil {
+		return x.PointsEarned
+	}
+	return 0
+}
+
+func (x *PBPayment) GetPointsRedeemed() int64 {
+	if x != nil {
+		return x.PointsRedeemed
+	}
+	return 0
+}
+
+func (x *PBPayment) GetPointsAmount() float64 {
+	if x != nil {
+		return x.PointsAmount
+	}
+	return 0
+}
+
+func (x *PBPayment) GetPointsBalance() int64 {
+	if x != nil {
+		return x.PointsBalance
+	}
+	return 0
+}
+
+func (x *PBPayment) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PBPayment) GetRounding() float64 {
+	if x != nil {
+		return x.Rounding
+	}
+	return 0
+}
+
+func (x *PBPayment) GetCardCurrency() string {
+	if x != nil {
+		return x.CardCurrency
+	}
+	return ""
+}
+
+func (x *PBPayment) GetFxRate() float64 {
+	if x != nil {
+		return x.FxRate
+	}
+	return 0
+}
+
+func (x *PBPayment) GetCardAmount() float64 {
+	if x != nil {
+		return x.CardAmount
+	}
+	return 0
+}
+
+func (x *PBPayment) GetCardNumber() string {
+	if x != nil {
+		return x.CardNumber
+	}
+	return ""
+}
+
 var File_payment_proto protoreflect.FileDescriptor
 
 var file_payment_proto_rawDesc = []byte{
 	0x0a, 0x0d, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
-	0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x22, 0xb7, 0x01, 0x0a, 0x09, 0x50, 0x42, 0x50, 0x61, 0x79,
+	0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x22, 0xf3, 0x04, 0x0a, 0x09, 0x50, 0x42, 0x50, 0x61, 0x79,
 	0x6d, 0x65, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x4e,
 	0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x69, 0x6e, 0x76,
 	0x6f, 0x69, 0x63, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61,
@@ -115,7 +219,35 @@ var file_payment_proto_rawDesc = []byte{
 	0x70, 0x61, 0x69, 0x64, 0x12, 0x2a, 0x0a, 0x10, 0x66, 0x69, 0x6e, 0x54, 0x72, 0x61, 0x6e, 0x73,
 	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10,
 	0x66, 0x69, 0x6e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44,
-	0x42, 0x03, 0x5a, 0x01, 0x2e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x12, 0x2a, 0x0a, 0x10, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x70, 0x72, 0x6f, 0x64,
+	0x75, 0x63, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x24, 0x0a, 0x0d,
+	0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x68,
+	0x6f, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x65, 0x72, 0x49, 0x64,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x65, 0x72,
+	0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x45, 0x61, 0x72, 0x6e,
+	0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73,
+	0x45, 0x61, 0x72, 0x6e, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73,
+	0x52, 0x65, 0x64, 0x65, 0x65, 0x6d, 0x65, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x64, 0x65, 0x65, 0x6d, 0x65, 0x64, 0x12, 0x22,
+	0x0a, 0x0c, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x41, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x42, 0x61, 0x6c, 0x61,
+	0x6e, 0x63, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x73, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x18, 0x0e, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x12, 0x22, 0x0a, 0x0c, 0x63, 0x61, 0x72, 0x64, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79,
+	0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x61, 0x72, 0x64, 0x43, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x78, 0x52, 0x61, 0x74, 0x65, 0x18, 0x10,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x66, 0x78, 0x52, 0x61, 0x74, 0x65, 0x12, 0x1e, 0x0a, 0x0a,
+	0x63, 0x61, 0x72, 0x64, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x0a, 0x63, 0x61, 0x72, 0x64, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1e, 0x0a, 0x0a,
+	0x63, 0x61, 0x72, 0x64, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x12, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x63, 0x61, 0x72, 0x64, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x42, 0x03, 0x5a, 0x01,
+	0x2e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -0,0 +1,271 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.1
+// 	protoc        v5.26.1
+// source: pricing.proto
+
+package types
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PBPriceChange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChangeId         string   `protobuf:"bytes,1,opt,name=changeId,proto3" json:"changeId,omitempty"`
+	ChangeDateTime   string   `protobuf:"bytes,2,opt,name=changeDateTime,proto3" json:"changeDateTime,omitempty"`
+	ChangeTimestamp  string   `protobuf:"bytes,3,opt,name=changeTimestamp,proto3" json:"changeTimestamp,omitempty"`
+	ProductId        string   `protobuf:"bytes,4,opt,name=productId,proto3" json:"productId,omitempty"`
+	ProductName      string   `protobuf:"bytes,5,opt,name=productName,proto3" json:"productName,omitempty"`
+	Category         string   `protobuf:"bytes,6,opt,name=category,proto3" json:"category,omitempty"`
+	OldPrice         float64  `protobuf:"fixed64,7,opt,name=oldPrice,proto3" json:"oldPrice,omitempty"`
+	NewPrice         float64  `protobuf:"fixed64,8,opt,name=newPrice,proto3" json:"newPrice,omitempty"`
+	Currency         string   `protobuf:"bytes,9,opt,name=currency,proto3" json:"currency,omitempty"`
+	Reason           string   `protobuf:"bytes,10,opt,name=reason,proto3" json:"reason,omitempty"`
+	ProduceTimestamp int64    `protobuf:"varint,11,opt,name=produceTimestamp,proto3" json:"produceTimestamp,omitempty"`
+	PriceList        string   `protobuf:"bytes,12,opt,name=priceList,proto3" json:"priceList,omitempty"`
+	PriceTier        float64  `protobuf:"fixed64,13,opt,name=priceTier,proto3" json:"priceTier,omitempty"`
+	StoreIds         []string `protobuf:"bytes,14,rep,name=storeIds,proto3" json:"storeIds,omitempty"`
+}
+
+func (x *PBPriceChange) Reset() {
+	*x = PBPriceChange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pricing_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PBPriceChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PBPriceChange) ProtoMessage() {}
+
+func (x *PBPriceChange) ProtoReflect() protoreflect.Message {
+	mi := &file_pricing_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PBPriceChange.ProtoReflect.Descriptor instead.
+func (*PBPriceChange) Descriptor() ([]byte, []int) {
+	return file_pricing_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PBPriceChange) GetChangeId() string {
+	if x != nil {
+		return x.ChangeId
+	}
+	return ""
+}
+
+func (x *PBPriceChange) GetChangeDateTime() string {
+	if x != nil {
+		return x.ChangeDateTime
+	}
+	return ""
+}
+
+func (x *PBPriceChange) GetChangeTimestamp() string {
+	if x != nil {
+		return x.ChangeTimestamp
+	}
+	return ""
+}
+
+func (x *PBPriceChange) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *PBPriceChange) GetProductName() string {
+	if x != nil {
+		return x.ProductName
+	}
+	return ""
+}
+
+func (x *PBPriceChange) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *PBPriceChange) GetOldPrice() float64 {
+	if x != nil {
+		return x.OldPrice
+	}
+	return 0
+}
+
+func (x *PBPriceChange) GetNewPrice() float64 {
+	if x != nil {
+		return x.NewPrice
+	}
+	return 0
+}
+
+func (x *PBPriceChange) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PBPriceChange) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PBPriceChange) GetProduceTimestamp() int64 {
+	if x != nil {
+		return x.ProduceTimestamp
+	}
+	return 0
+}
+
+func (x *PBPriceChange) GetPriceList() string {
+	if x != nil {
+		return x.PriceList
+	}
+	return ""
+}
+
+func (x *PBPriceChange) GetPriceTier() float64 {
+	if x != nil {
+		return x.PriceTier
+	}
+	return 0
+}
+
+func (x *PBPriceChange) GetStoreIds() []string {
+	if x != nil {
+		return x.StoreIds
+	}
+	return nil
+}
+
+var File_pricing_proto protoreflect.FileDescriptor
+
+var file_pricing_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x70, 0x72, 0x69, 0x63, 0x69, 0x6e, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x22, 0xc9, 0x03, 0x0a, 0x0d, 0x50, 0x42, 0x50, 0x72, 0x69,
+	0x63, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x49, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x44, 0x61,
+	0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x44, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x28, 0x0a, 0x0f,
+	0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63,
+	0x74, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x64, 0x75,
+	0x63, 0x74, 0x49, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x4e,
+	0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x64, 0x75,
+	0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f,
+	0x72, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f,
+	0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x6c, 0x64, 0x50, 0x72, 0x69, 0x63, 0x65, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x6f, 0x6c, 0x64, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x6e, 0x65, 0x77, 0x50, 0x72, 0x69, 0x63, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x08, 0x6e, 0x65, 0x77, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x2a,
+	0x0a, 0x10, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63,
+	0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72,
+	0x69, 0x63, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70,
+	0x72, 0x69, 0x63, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x69, 0x63,
+	0x65, 0x54, 0x69, 0x65, 0x72, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x70, 0x72, 0x69,
+	0x63, 0x65, 0x54, 0x69, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x49,
+	0x64, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x49,
+	0x64, 0x73, 0x42, 0x03, 0x5a, 0x01, 0x2e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pricing_proto_rawDescOnce sync.Once
+	file_pricing_proto_rawDescData = file_pricing_proto_rawDesc
+)
+
+func file_pricing_proto_rawDescGZIP() []byte {
+	file_pricing_proto_rawDescOnce.Do(func() {
+		file_pricing_proto_rawDescData = protoimpl.X.CompressGZIP(file_pricing_proto_rawDescData)
+	})
+	return file_pricing_proto_rawDescData
+}
+
+var file_pricing_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_pricing_proto_goTypes = []interface{}{
+	(*PBPriceChange)(nil), // 0: types.PBPriceChange
+}
+var file_pricing_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_pricing_proto_init() }
+func file_pricing_proto_init() {
+	if File_pricing_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pricing_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PBPriceChange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pricing_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pricing_proto_goTypes,
+		DependencyIndexes: file_pricing_proto_depIdxs,
+		MessageInfos:      file_pricing_proto_msgTypes,
+	}.Build()
+	File_pricing_proto = out.File
+	file_pricing_proto_rawDesc = nil
+	file_pricing_proto_goTypes = nil
+	file_pricing_proto_depIdxs = nil
+}